@@ -1,5 +1,5 @@
 -- sqlfluff:dialect:sqlite
 -- sqlfluff:templater:placeholder:param_style:question_mark
-SELECT migration_id
+SELECT COALESCE(MAX(migration_id), 0) AS migration_id
 FROM geese_migrations
 WHERE namespace = ?;
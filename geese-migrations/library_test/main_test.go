@@ -101,3 +101,34 @@ func TestProcessDowngrades(t *testing.T) {
 		t.Fatalf("expected error querying dropped table, but got none")
 	}
 }
+
+func TestAutoUpgradeMultipleRuns(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("could not get cwd: %v", err)
+	}
+
+	dbFile := filepath.Join(cwd, "test_multiple.db")
+	defer os.Remove(dbFile)
+
+	dirPath := filepath.Join(cwd, "test_migrations_multiple")
+
+	// Only the pending migrations should be applied on subsequent runs
+	for range 2 {
+		err = library.AutoUpgrade("test", dirPath, "sqlite3", dbFile)
+		if err != nil {
+			t.Fatalf("processMigrations failed: %v", err)
+		}
+	}
+
+	db, err := sql.Open("sqlite3", dbFile)
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec("SELECT * FROM link")
+	if err != nil {
+		t.Fatalf("unexpected error querying new table: %v", err)
+	}
+}
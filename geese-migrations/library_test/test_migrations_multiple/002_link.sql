@@ -0,0 +1,8 @@
+-- sqlfluff:dialect:duckdb
+-- +geese up
+CREATE TABLE link (
+    source VARCHAR NOT NULL,
+    target VARCHAR NOT NULL
+);
+-- +geese down
+DROP TABLE IF EXISTS link;
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strconv"
 )
 
 func GetSyncDir() string {
@@ -21,3 +22,31 @@ func GetSyncDir() string {
 func GetSubDir() string {
 	return os.Getenv("SHEARS_SUBDIR")
 }
+
+// GetJournalSubDir is the subDir for daily journal notes
+func GetJournalSubDir() string {
+	if subDir := os.Getenv("SHEARS_JOURNAL_SUBDIR"); subDir != "" {
+		return subDir
+	}
+
+	return "journal"
+}
+
+// GetTrashRetentionDays is the number of days that trashed notes are kept before being purged
+func GetTrashRetentionDays() int {
+	if days, err := strconv.Atoi(os.Getenv("SHEARS_TRASH_RETENTION_DAYS")); err == nil {
+		return days
+	}
+
+	return 30
+}
+
+// Migrations are read from the source checkout because the geese library requires a directory
+func GetMigrationsDir() string {
+	yakShearsDir := os.Getenv("YAK_SHEARS_DIR")
+	if yakShearsDir == "" {
+		return ""
+	}
+
+	return filepath.Join(yakShearsDir, "yak-notes-cli", "migrations")
+}
@@ -9,10 +9,27 @@ import (
 
 func InitCli() (cli *clir.Cli) {
 	cli = clir.NewCli("yak-shears", "Simple note taking", "v0.0.1")
+	subcommands.AttachAgenda(cli)
+	subcommands.AttachArchive(cli)
+	subcommands.AttachBacklinks(cli)
+	subcommands.AttachCheckLinks(cli)
+	subcommands.AttachDoctor(cli)
+	subcommands.AttachEdit(cli)
+	subcommands.AttachFmt(cli)
+	subcommands.AttachLink(cli)
 	subcommands.AttachList(cli)
+	subcommands.AttachMerge(cli)
+	subcommands.AttachMove(cli)
 	subcommands.AttachNew(cli)
 	subcommands.AttachRename(cli)
+	subcommands.AttachRestore(cli)
 	subcommands.AttachSearch(cli)
+	subcommands.AttachShow(cli)
+	subcommands.AttachSplit(cli)
+	subcommands.AttachState(cli)
+	subcommands.AttachStats(cli)
+	subcommands.AttachToday(cli)
+	subcommands.AttachTrash(cli)
 
 	return
 }
@@ -0,0 +1,81 @@
+package subcommands
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/leaanthony/clir"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/config"
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+)
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(time.DateOnly)
+}
+
+func summarizeAgenda(vault *notes.Vault, items []notes.AgendaItem, progress map[string]notes.Progress) string {
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"Category", "State", "Note", "Progress", "Start", "Soft Deadline", "Hard Deadline"})
+
+	for _, item := range items {
+		t.AppendRow([]interface{}{
+			item.Category,
+			item.State,
+			vault.RelPath(item.Stat.Path),
+			progress[item.Stat.Path].String(),
+			formatDate(item.Schedule.Start),
+			formatDate(item.Schedule.SoftDeadline),
+			formatDate(item.Schedule.HardDeadline),
+		})
+	}
+
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Name: "Category", AutoMerge: true},
+		{Name: "State", AutoMerge: true},
+	})
+
+	return t.Render()
+}
+
+func AttachAgenda(cli *clir.Cli) {
+	agendaCmd := cli.NewSubCommand("agenda", "Show overdue, due, startable, and upcoming tasks")
+
+	syncDir := config.GetSyncDir()
+	agendaCmd.StringFlag("sync-dir", "Sync Directory", &syncDir)
+
+	days := 7
+	agendaCmd.IntFlag("days", "Number of days to look ahead for upcoming tasks", &days)
+
+	agendaCmd.Action(func() (err error) {
+		vault, err := openVault(syncDir)
+		if err != nil {
+			return
+		}
+
+		items, warnings, err := vault.Agenda(time.Now(), days)
+		if err != nil {
+			return
+		}
+
+		for _, warning := range warnings {
+			log.Printf("Warning: %s\n", warning)
+		}
+
+		progress, err := vault.TaskProgress()
+		if err != nil {
+			return
+		}
+
+		fmt.Println(summarizeAgenda(vault, items, progress))
+		warnOpenSubtasks(vault, progress)
+
+		return
+	})
+}
@@ -0,0 +1,191 @@
+package subcommands
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/leaanthony/clir"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/config"
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+)
+
+// Warn about the notes that still link to a removed note
+func warnLinkSources(vault *notes.Vault, path string) error {
+	sources, err := vault.LinkSources(path)
+	if err != nil {
+		return err
+	}
+
+	for _, source := range sources {
+		log.Printf("Warning: %s links to %s\n", source, vault.RelPath(path))
+	}
+
+	return nil
+}
+
+type ArchiveFlags struct {
+	Note    string `description:"Note to archive. Interactively selected when omitted" pos:"1"`
+	SyncDir string `description:"Sync Directory" name:"sync-dir"`
+}
+
+func archiveAction(flags *ArchiveFlags) (err error) {
+	if flags.SyncDir == "" {
+		flags.SyncDir = config.GetSyncDir()
+	}
+
+	vault, err := openVault(flags.SyncDir)
+	if err != nil {
+		return
+	}
+
+	stat, err := resolveOrPickNote(vault, flags.Note)
+	if err != nil {
+		return
+	}
+
+	path, err := vault.Archive(stat.Path)
+	if err != nil {
+		return
+	}
+
+	fmt.Printf("Archived %s to %s\n", vault.RelPath(stat.Path), vault.RelPath(path))
+
+	if err := warnLinkSources(vault, stat.Path); err != nil {
+		return err
+	}
+
+	return vault.RemoveIndexed(stat.Path)
+}
+
+type TrashFlags struct {
+	Note          string `description:"Note to trash. Interactively selected when omitted" pos:"1"`
+	SyncDir       string `description:"Sync Directory" name:"sync-dir"`
+	Purge         bool   `description:"If set, only purge expired notes from the trash" name:"purge"`
+	RetentionDays int    `description:"Days to keep trashed notes, where 0 purges every note. Defaults to $SHEARS_TRASH_RETENTION_DAYS or 30" name:"retention-days" default:"-1"`
+}
+
+func trashAction(flags *TrashFlags) (err error) {
+	if flags.SyncDir == "" {
+		flags.SyncDir = config.GetSyncDir()
+	}
+
+	if flags.RetentionDays < 0 {
+		flags.RetentionDays = config.GetTrashRetentionDays()
+	}
+
+	vault, err := openVault(flags.SyncDir)
+	if err != nil {
+		return
+	}
+
+	if !flags.Purge {
+		stat, err := resolveOrPickNote(vault, flags.Note)
+		if err != nil {
+			return err
+		}
+
+		path, err := vault.Trash(stat.Path)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Trashed %s to %s\n", vault.RelPath(stat.Path), vault.RelPath(path))
+
+		if err := warnLinkSources(vault, stat.Path); err != nil {
+			return err
+		}
+
+		if err := vault.RemoveIndexed(stat.Path); err != nil {
+			return err
+		}
+	}
+
+	purged, err := vault.PurgeTrash(time.Now(), time.Duration(flags.RetentionDays)*24*time.Hour)
+	for _, path := range purged {
+		fmt.Printf("Purged %s\n", vault.RelPath(path))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	plan, err := vault.PlanPurgedUnlinks(purged)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(vault.FormatPlan(plan))
+
+	if err := vault.Apply(plan); err != nil {
+		return fmt.Errorf("failed to remove links to purged notes: %w", err)
+	}
+
+	return nil
+}
+
+type RestoreFlags struct {
+	Note    string `description:"Archived or trashed note to restore. Interactively selected when omitted" pos:"1"`
+	SyncDir string `description:"Sync Directory" name:"sync-dir"`
+}
+
+func restoreAction(flags *RestoreFlags) (err error) {
+	if flags.SyncDir == "" {
+		flags.SyncDir = config.GetSyncDir()
+	}
+
+	vault, err := openVault(flags.SyncDir)
+	if err != nil {
+		return
+	}
+
+	var stat notes.FileStat
+	if flags.Note != "" {
+		if stat, err = vault.ResolveRemoved(flags.Note); err != nil {
+			return
+		}
+	} else {
+		removed := []notes.FileStat{}
+
+		for _, dir := range []string{notes.TrashDir, notes.ArchiveDir} {
+			stats, err := vault.ListRemoved(dir)
+			if err != nil {
+				return err
+			}
+
+			removed = append(removed, stats...)
+		}
+
+		sort.Slice(removed, func(i, j int) bool {
+			return removed[i].ModTime.After(removed[j].ModTime)
+		})
+
+		if stat, err = pickFrom(removed, os.Stdin, os.Stdout); err != nil {
+			return
+		}
+	}
+
+	path, err := vault.Restore(stat.Path)
+	if err != nil {
+		return
+	}
+
+	fmt.Printf("Restored %s to %s\n", vault.RelPath(stat.Path), vault.RelPath(path))
+
+	return vault.AddIndexed(path)
+}
+
+func AttachArchive(cli *clir.Cli) {
+	cli.NewSubCommandFunction("archive", "Move a note into the hidden archive folder", archiveAction)
+}
+
+func AttachTrash(cli *clir.Cli) {
+	cli.NewSubCommandFunction("trash", "Move a note into the hidden trash folder and purge expired notes", trashAction)
+}
+
+func AttachRestore(cli *clir.Cli) {
+	cli.NewSubCommandFunction("restore", "Restore an archived or trashed note to its original subDir", restoreAction)
+}
@@ -0,0 +1,80 @@
+package subcommands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/leaanthony/clir"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/config"
+)
+
+var errBrokenLinks = errors.New("broken links were found")
+
+type CheckLinksFlags struct {
+	SyncDir string `description:"Sync Directory" name:"sync-dir"`
+	Fix     bool   `description:"If set, rewrite links that have exactly one replacement" name:"fix"`
+}
+
+func checkLinksAction(flags *CheckLinksFlags) (err error) {
+	if flags.SyncDir == "" {
+		flags.SyncDir = config.GetSyncDir()
+	}
+
+	vault, err := openVault(flags.SyncDir)
+	if err != nil {
+		return
+	}
+
+	broken, err := vault.CheckLinks()
+	if err != nil {
+		return
+	}
+
+	unfixed := 0
+
+	for _, link := range broken {
+		proposal := "no replacement found"
+		if fix, ok := link.Fix(); ok {
+			proposal = "fix: " + fix
+		} else {
+			unfixed++
+
+			if len(link.Candidates) > 1 {
+				proposal = "candidates: " + strings.Join(link.Candidates, ", ")
+			}
+		}
+
+		fmt.Printf("%s -> %s (%s)\n", link.Source, link.Target, proposal)
+	}
+
+	if flags.Fix {
+		plan, err := vault.PlanLinkFixes(broken)
+		if err != nil {
+			return err
+		}
+
+		fmt.Print(vault.FormatPlan(plan))
+
+		if err := vault.Apply(plan); err != nil {
+			return fmt.Errorf("failed to fix links: %w", err)
+		}
+	} else {
+		unfixed = len(broken)
+	}
+
+	if unfixed > 0 {
+		return fmt.Errorf("%w: %d unresolved", errBrokenLinks, unfixed)
+	}
+
+	return
+}
+
+func AttachCheckLinks(cli *clir.Cli) {
+	cli.NewSubCommandFunction(
+		"check-links",
+		"Find links to missing notes and optionally fix them",
+		checkLinksAction,
+	)
+}
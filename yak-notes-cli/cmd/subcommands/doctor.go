@@ -0,0 +1,74 @@
+package subcommands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/leaanthony/clir"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/config"
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+)
+
+var errVaultProblems = errors.New("problems were found in the vault")
+
+type DoctorFlags struct {
+	SyncDir string `description:"Sync Directory" name:"sync-dir"`
+	Fix     bool   `description:"If set, apply the automatic fixes" name:"fix"`
+}
+
+func doctorAction(flags *DoctorFlags) (err error) {
+	if flags.SyncDir == "" {
+		flags.SyncDir = config.GetSyncDir()
+	}
+
+	vault, err := openVault(flags.SyncDir)
+	if err != nil {
+		return
+	}
+
+	findings, err := vault.Doctor()
+	if err != nil {
+		return
+	}
+
+	for _, finding := range findings {
+		fmt.Println(finding)
+	}
+
+	fixed := map[string]bool{}
+
+	if flags.Fix {
+		done, err := vault.FixFindings(findings)
+		for _, finding := range done {
+			fmt.Printf("Fixed %s: %s\n", finding.Path, finding.Fix)
+			fixed[finding.String()] = true
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	unfixed := 0
+
+	for _, finding := range findings {
+		if finding.Severity == notes.SeverityError && !fixed[finding.String()] {
+			unfixed++
+		}
+	}
+
+	if unfixed > 0 {
+		return fmt.Errorf("%w: %d errors", errVaultProblems, unfixed)
+	}
+
+	return
+}
+
+func AttachDoctor(cli *clir.Cli) {
+	cli.NewSubCommandFunction(
+		"doctor",
+		"Audit the notes and the index and optionally apply the automatic fixes",
+		doctorAction,
+	)
+}
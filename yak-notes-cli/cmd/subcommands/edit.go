@@ -0,0 +1,35 @@
+package subcommands
+
+import (
+	"github.com/leaanthony/clir"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/config"
+)
+
+type EditFlags struct {
+	Note    string `description:"Note to edit. Interactively selected when omitted" pos:"1"`
+	SyncDir string `description:"Sync Directory" name:"sync-dir"`
+	Line    int    `description:"Line to place the cursor on" name:"line"`
+}
+
+func editAction(flags *EditFlags) (err error) {
+	if flags.SyncDir == "" {
+		flags.SyncDir = config.GetSyncDir()
+	}
+
+	vault, err := openVault(flags.SyncDir)
+	if err != nil {
+		return
+	}
+
+	stat, err := resolveOrPickNote(vault, flags.Note)
+	if err != nil {
+		return
+	}
+
+	return launchEditor(stat.Path, flags.Line)
+}
+
+func AttachEdit(cli *clir.Cli) {
+	cli.NewSubCommandFunction("edit", "Open a note in `$VISUAL` or `$EDITOR`", editAction)
+}
@@ -0,0 +1,157 @@
+package subcommands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+var errUnclosedQuote = errors.New("unclosed quote")
+
+// Split a command line on whitespace, which can be quoted or escaped with a backslash
+//
+// A backslash only escapes a quote or whitespace and is otherwise kept, so that Windows paths keep their separators
+func splitArgs(command string) ([]string, error) {
+	args := []string{}
+
+	var (
+		current strings.Builder
+		quote   rune
+		inArg   bool
+	)
+
+	runes := []rune(command)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case r == '\\' && quote != '\'' && i+1 < len(runes) && strings.ContainsRune("\"' \t\n", runes[i+1]):
+			i++
+			current.WriteRune(runes[i])
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("%w in '%s'", errUnclosedQuote, command)
+	}
+
+	if inArg {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
+
+// Split the command of an environment variable, where a program path with unquoted spaces, such as
+// `C:\Program Files\...\code.exe --wait`, is joined back like Windows does, to the shortest prefix that is a program
+func splitCommand(command string) ([]string, error) {
+	args, err := splitArgs(command)
+	if err != nil || len(args) < 2 {
+		return args, err
+	}
+
+	if _, err := exec.LookPath(args[0]); err == nil {
+		return args, nil
+	}
+
+	for n := 2; n <= len(args); n++ {
+		if program := strings.Join(args[:n], " "); isProgramPath(program) {
+			return append([]string{program}, args[n:]...), nil
+		}
+	}
+
+	return args, nil
+}
+
+// A path to a program rather than a name that is looked up in $PATH
+func isProgramPath(path string) bool {
+	if !strings.ContainsAny(path, `/\`) {
+		return false
+	}
+
+	_, err := exec.LookPath(path)
+
+	return err == nil
+}
+
+// The editor from `$VISUAL`, then `$EDITOR`, then the platform default
+func editorArgs() ([]string, error) {
+	for _, key := range []string{"VISUAL", "EDITOR"} {
+		if value := strings.TrimSpace(os.Getenv(key)); value != "" {
+			args, err := splitCommand(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", key, err)
+			}
+
+			return args, nil
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}, nil
+	}
+
+	return []string{"vi"}, nil
+}
+
+// Add the path with the line hint in the format that the editor understands
+func withLineHint(args []string, path string, line int) []string {
+	if line <= 0 {
+		return append(args, path)
+	}
+
+	name := strings.TrimSuffix(filepath.Base(args[0]), ".exe")
+	switch name {
+	case "code", "code-insiders", "codium":
+		return append(args, "--goto", path+":"+strconv.Itoa(line))
+	case "subl", "zed":
+		return append(args, path+":"+strconv.Itoa(line))
+	default:
+		return append(args, "+"+strconv.Itoa(line), path)
+	}
+}
+
+// Run the editor in the foreground with the terminal attached. The line is ignored when not positive
+func launchEditor(path string, line int) error {
+	args, err := editorArgs()
+	if err != nil {
+		return err
+	}
+
+	args = withLineHint(args, path, line)
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run editor '%s': %w", strings.Join(args, " "), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package subcommands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/leaanthony/clir"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/config"
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+)
+
+var errUnformatted = errors.New("notes are not formatted")
+
+// Format the referenced notes, or every note in the vault when there are none
+func formatNotes(syncDir string, refs []string, check bool) error {
+	vault, err := openVault(syncDir)
+	if err != nil {
+		return err
+	}
+
+	stats := []notes.FileStat{}
+
+	if len(refs) == 0 {
+		if stats, err = vault.ListNotes(""); err != nil {
+			return err
+		}
+	}
+
+	for _, ref := range refs {
+		stat, err := vault.Resolve(ref)
+		if err != nil {
+			return fmt.Errorf("failed to find note: %w", err)
+		}
+
+		stats = append(stats, stat)
+	}
+
+	unformatted := 0
+
+	for _, stat := range stats {
+		issues, err := notes.FormatFile(stat.Path, check)
+		if err != nil {
+			return err
+		}
+
+		if len(issues) == 0 {
+			continue
+		}
+
+		unformatted++
+
+		if !check {
+			fmt.Printf("Formatted %s\n", vault.RelPath(stat.Path))
+			continue
+		}
+
+		for _, issue := range issues {
+			fmt.Printf("%s:%s\n", vault.RelPath(stat.Path), issue)
+		}
+	}
+
+	if check && unformatted > 0 {
+		return fmt.Errorf("%w: %d of %d", errUnformatted, unformatted, len(stats))
+	}
+
+	return nil
+}
+
+func AttachFmt(cli *clir.Cli) {
+	fmtCmd := cli.NewSubCommand("fmt", "Rewrite notes in the canonical djot style")
+
+	syncDir := config.GetSyncDir()
+	fmtCmd.StringFlag("sync-dir", "Sync Directory", &syncDir)
+
+	check := false
+	fmtCmd.BoolFlag("check", "If set, list the issues and exit with an error instead of rewriting", &check)
+
+	fmtCmd.Action(func() error {
+		return formatNotes(syncDir, fmtCmd.OtherArgs(), check)
+	})
+}
@@ -0,0 +1,97 @@
+package subcommands
+
+import (
+	"fmt"
+
+	"github.com/leaanthony/clir"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/config"
+)
+
+type LinkFlags struct {
+	From    string `description:"Note to add the link to. Interactively selected when omitted" pos:"1"`
+	To      string `description:"Note to link to. Interactively selected when omitted" pos:"2"`
+	SyncDir string `description:"Sync Directory" name:"sync-dir"`
+}
+
+func linkAction(flags *LinkFlags) (err error) {
+	if flags.SyncDir == "" {
+		flags.SyncDir = config.GetSyncDir()
+	}
+
+	vault, err := openVault(flags.SyncDir)
+	if err != nil {
+		return
+	}
+
+	from, err := resolveOrPickNote(vault, flags.From)
+	if err != nil {
+		return
+	}
+
+	to, err := resolveOrPickNote(vault, flags.To)
+	if err != nil {
+		return
+	}
+
+	added, err := vault.AddLink(from.Path, to.Path)
+	if err != nil {
+		return
+	}
+
+	if added {
+		fmt.Printf("Linked %s to %s\n", vault.RelPath(from.Path), vault.RelPath(to.Path))
+	} else {
+		fmt.Printf("%s already links to %s\n", vault.RelPath(from.Path), vault.RelPath(to.Path))
+	}
+
+	return
+}
+
+type BacklinksFlags struct {
+	Note    string `description:"Note to find backlinks for. Interactively selected when omitted" pos:"1"`
+	SyncDir string `description:"Sync Directory" name:"sync-dir"`
+}
+
+func backlinksAction(flags *BacklinksFlags) (err error) {
+	if flags.SyncDir == "" {
+		flags.SyncDir = config.GetSyncDir()
+	}
+
+	vault, err := openVault(flags.SyncDir)
+	if err != nil {
+		return
+	}
+
+	stat, err := resolveOrPickNote(vault, flags.Note)
+	if err != nil {
+		return
+	}
+
+	sources, err := vault.Backlinks(stat.Path)
+	if err != nil {
+		return
+	}
+
+	for _, source := range sources {
+		fmt.Println(source)
+	}
+
+	return
+}
+
+func AttachLink(cli *clir.Cli) {
+	cli.NewSubCommandFunction(
+		"link",
+		"Add a link from one note to another",
+		linkAction,
+	)
+}
+
+func AttachBacklinks(cli *clir.Cli) {
+	cli.NewSubCommandFunction(
+		"backlinks",
+		"List the notes that link to a note",
+		backlinksAction,
+	)
+}
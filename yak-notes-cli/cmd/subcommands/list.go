@@ -1,174 +1,125 @@
 package subcommands
 
 import (
-	"bufio"
 	"fmt"
-	"io/fs"
-	"os"
-	"path/filepath"
+	"log"
 	"slices"
 	"sort"
 	"strings"
 	"time"
 
-	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/config"
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
 	"github.com/leaanthony/clir"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/config"
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
 )
 
 // Shared Utilities
 
-func ListsubDirs(dir string) (folderNames []string, err error) {
-	files, err := os.ReadDir(dir)
+func openVault(syncDir string) (*notes.Vault, error) {
+	vault, err := notes.NewVault(syncDir)
 	if err != nil {
-		return
+		return nil, fmt.Errorf("failed to open vault: %w", err)
 	}
 
-	for _, file := range files {
-		if file.IsDir() && !(strings.HasPrefix(file.Name(), ".")) {
-			folderNames = append(folderNames, file.Name())
+	vault.MigrationsDir = config.GetMigrationsDir()
+
+	return vault, nil
+}
+
+// Warn about completed notes that still have open subtasks
+func warnOpenSubtasks(vault *notes.Vault, progress map[string]notes.Progress) {
+	paths := []string{}
+
+	for path, p := range progress {
+		if p.Inconsistent() {
+			paths = append(paths, path)
 		}
 	}
 
-	return
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		log.Printf("Warning: %s is complete, but has %d open subtasks (%s)\n",
+			vault.RelPath(path), progress[path].Open(), progress[path])
+	}
 }
 
 // Sort Helpers
 
-type FileStat struct {
-	file     fs.DirEntry
-	fileInfo fs.FileInfo
-	subDir   string
-	path     string
-}
+type SortMethod func([]notes.FileStat)
 
-type SortMethod func([]FileStat)
-
-func sortFileName(stats []FileStat) {
+func sortFileName(stats []notes.FileStat) {
 	sort.Slice(stats, func(i, j int) bool {
-		return stats[i].file.Name() > stats[j].file.Name()
+		return stats[i].Name > stats[j].Name
 	})
 }
 
-func sortFileModTime(stats []FileStat) {
+func sortFileModTime(stats []notes.FileStat) {
 	sort.Slice(stats, func(i, j int) bool {
-		return stats[i].fileInfo.ModTime().After(stats[j].fileInfo.ModTime())
+		return stats[i].ModTime.After(stats[j].ModTime)
 	})
 }
 
 // Output
 
 type FileSummary struct {
-	stat   FileStat
-	header string
+	stat     notes.FileStat
+	header   notes.Header
+	title    string
+	progress notes.Progress
 }
 
 type OutputFormat func([]FileSummary) string
 
 func summarize(summaries []FileSummary) string {
+	titleCol := "Title"
 	modTimeCol := "Modified"
+	headerCol := "Header"
 
 	t := table.NewWriter()
-	t.AppendHeader(table.Row{"subDir", "File Name", modTimeCol, "Header"})
+	t.AppendHeader(table.Row{"subDir", "File Name", titleCol, modTimeCol, "State", "Progress", headerCol})
 
 	for _, summary := range summaries {
 		stat := summary.stat
+		value, _ := summary.header.Get(notes.KeyState)
 		t.AppendRow([]interface{}{
-			stat.subDir, stat.file.Name(), stat.fileInfo.ModTime(), summary.header,
+			stat.SubDir, stat.Name, summary.title, stat.ModTime, value, summary.progress.String(), strings.Join(summary.header.Keys(), ", "),
 		})
 	}
 
 	t.SetColumnConfigs([]table.ColumnConfig{{
+		Name:     titleCol,
+		WidthMax: 40,
+	}, {
 		Name:        modTimeCol,
 		Transformer: text.NewTimeTransformer(time.RFC822, nil), // "02 Jan 06 15:04 MST"
+	}, {
+		Name:     headerCol,
+		WidthMax: 40,
 	}})
 
 	return t.Render()
 }
 
-func readMeta(path string) (string, error) {
-	// Adapted from: https://www.bytesizego.com/blog/reading-file-line-by-line-golang
-	file, err := os.Open(path)
-	if err != nil {
-		return "", fmt.Errorf("failed to open file %s: %w", path, err)
-	}
-	defer file.Close()
-
-	// Read file line by line
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		return line, nil
-	}
-
-	if err := scanner.Err(); err != nil {
-		return "", fmt.Errorf("error reading file %s: %w", path, err)
-	}
-
-	return "", nil
-}
-
-func enrich(stat FileStat) (fs FileSummary, err error) {
-	header, err := readMeta(stat.path)
+func enrich(stat notes.FileStat, progress map[string]notes.Progress) (fs FileSummary, err error) {
+	doc, err := notes.ReadDocument(stat.Path)
 	if err != nil {
 		return
 	}
 
 	fs.stat = stat
-	fs.header = header
+	fs.header = doc.Header
+	fs.title = doc.Title()
+	fs.progress = progress[stat.Path]
 
 	return
 }
 
 // Main Operations
 
-func calculateStats(syncDir, subDir string) (stats []FileStat, err error) {
-	dir := filepath.Join(syncDir, subDir)
-
-	files, err := os.ReadDir(dir)
-	if err != nil {
-		return
-	}
-
-	for _, file := range files {
-		if !file.IsDir() && strings.HasSuffix(file.Name(), ".dj") {
-			fi, err := file.Info()
-			if err != nil {
-				return stats, fmt.Errorf("error with specified file (`%v`): %w", file, err)
-			}
-
-			stat := FileStat{
-				file:     file,
-				fileInfo: fi,
-				subDir:   subDir,
-				path:     filepath.Join(dir, file.Name()),
-			}
-			stats = append(stats, stat)
-		}
-	}
-
-	return
-}
-
-func getStats(syncDir string) (stats []FileStat, err error) {
-	folderNames, err := ListsubDirs(syncDir)
-	if err != nil {
-		return
-	}
-
-	for _, subDir := range folderNames {
-		subStats, err := calculateStats(syncDir, subDir)
-		if err != nil {
-			return stats, err
-		}
-
-		stats = append(stats, subStats...)
-	}
-
-	return
-}
-
 func AttachList(cli *clir.Cli) {
 	listCmd := cli.NewSubCommand("list", "List notes")
 
@@ -188,7 +139,12 @@ func AttachList(cli *clir.Cli) {
 		sortMethod := map[string]SortMethod{"name": sortFileName, "mod": sortFileModTime}[sortMethodStr]
 		output := map[string]OutputFormat{"text": summarize}[outputFormat]
 
-		stats, err := getStats(syncDir)
+		vault, err := openVault(syncDir)
+		if err != nil {
+			return
+		}
+
+		stats, err := vault.ListNotes("")
 		if err != nil {
 			return
 		}
@@ -199,10 +155,15 @@ func AttachList(cli *clir.Cli) {
 			slices.Reverse(stats)
 		}
 
+		progress, err := vault.TaskProgress()
+		if err != nil {
+			return
+		}
+
 		summaries := []FileSummary{}
 
 		for _, s := range stats {
-			summary, err := enrich(s)
+			summary, err := enrich(s, progress)
 			if err != nil {
 				return err
 			}
@@ -211,6 +172,7 @@ func AttachList(cli *clir.Cli) {
 		}
 
 		fmt.Println(output(summaries))
+		warnOpenSubtasks(vault, progress)
 
 		return
 	})
@@ -1 +1,62 @@
 package subcommands
+
+import (
+	"fmt"
+
+	"github.com/leaanthony/clir"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/config"
+)
+
+type MergeFlags struct {
+	From    string `description:"Note to merge and remove. Interactively selected when omitted" pos:"1"`
+	To      string `description:"Note to merge into. Interactively selected when omitted" pos:"2"`
+	SyncDir string `description:"Sync Directory" name:"sync-dir"`
+	DryRun  bool   `description:"If set, only print the planned file changes" name:"dry-run"`
+}
+
+func mergeAction(flags *MergeFlags) (err error) {
+	if flags.SyncDir == "" {
+		flags.SyncDir = config.GetSyncDir()
+	}
+
+	vault, err := openVault(flags.SyncDir)
+	if err != nil {
+		return
+	}
+
+	from, err := resolveOrPickNote(vault, flags.From)
+	if err != nil {
+		return
+	}
+
+	to, err := resolveOrPickNote(vault, flags.To)
+	if err != nil {
+		return
+	}
+
+	plan, err := vault.PlanMerge(from.Path, to.Path)
+	if err != nil {
+		return
+	}
+
+	fmt.Print(vault.FormatPlan(plan))
+
+	if flags.DryRun {
+		return
+	}
+
+	if err = vault.Apply(plan); err != nil {
+		return fmt.Errorf("failed to merge %s into %s: %w", from.Name, to.Name, err)
+	}
+
+	return
+}
+
+func AttachMerge(cli *clir.Cli) {
+	cli.NewSubCommandFunction(
+		"merge",
+		"Merge one note into another and redirect links",
+		mergeAction,
+	)
+}
@@ -0,0 +1,65 @@
+package subcommands
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/leaanthony/clir"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/config"
+)
+
+var errMissingSubDir = errors.New("a subDir to move the note into is required")
+
+type MoveFlags struct {
+	Note    string `description:"Note to move. Use '' to select interactively" pos:"1"`
+	SubDir  string `description:"SubDir to move the note into" pos:"2"`
+	SyncDir string `description:"Sync Directory" name:"sync-dir"`
+	DryRun  bool   `description:"If set, only print the planned file changes" name:"dry-run"`
+}
+
+func moveAction(flags *MoveFlags) (err error) {
+	if flags.SyncDir == "" {
+		flags.SyncDir = config.GetSyncDir()
+	}
+
+	vault, err := openVault(flags.SyncDir)
+	if err != nil {
+		return
+	}
+
+	if flags.SubDir == "" {
+		return errMissingSubDir
+	}
+
+	stat, err := resolveOrPickNote(vault, flags.Note)
+	if err != nil {
+		return
+	}
+
+	plan, err := vault.PlanMove(stat.Path, flags.SubDir)
+	if err != nil {
+		return
+	}
+
+	fmt.Print(vault.FormatPlan(plan))
+
+	if flags.DryRun {
+		return
+	}
+
+	if err = vault.Apply(plan); err != nil {
+		return fmt.Errorf("failed to move %s to %s: %w", stat.Name, flags.SubDir, err)
+	}
+
+	return vault.MoveIndexed(stat.Path, filepath.Join(vault.SyncDir, flags.SubDir, stat.Name))
+}
+
+func AttachMove(cli *clir.Cli) {
+	cli.NewSubCommandFunction(
+		"move",
+		"Move a note to another subDir and redirect links",
+		moveAction,
+	)
+}
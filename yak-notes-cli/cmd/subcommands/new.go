@@ -1,98 +1,155 @@
 package subcommands
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
-	"path/filepath"
-	"slices"
 	"strings"
 	"time"
 
 	"github.com/leaanthony/clir"
 
 	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/config"
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
 )
 
-func ToTimeName(t time.Time) string {
-	// Adapted from: https://stackoverflow.com/a/65221179/3219667
-	//  and https://pkg.go.dev/time
-	return strings.Replace(t.UTC().Format(time.RFC3339), ":", "_", 2) // or RFC9557?
+var errUnknownSource = errors.New("unknown note source")
+
+func promptLine(in io.Reader, out io.Writer, prompt string) (string, error) {
+	fmt.Fprint(out, prompt)
+
+	scanner := bufio.NewScanner(in)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return "", fmt.Errorf("failed to read input: %w", err)
+		}
+
+		return "", nil
+	}
+
+	return strings.TrimSpace(scanner.Text()), nil
 }
 
-func FromTimeName(name string) (time.Time, error) {
-	parsedName := strings.Replace(name, "_", ":", 2)
-	time, err := time.Parse(time.RFC3339, parsedName)
+// Render the template or the default template for the subDir. Notes without a template are empty
+//
+// The title is prompted for when the template uses it, unless known or the note is created non-interactively
+func renderNewNote(vault *notes.Vault, subDir, name string, created time.Time, title string, prompt bool) (string, error) {
+	if name == "" {
+		if name = vault.SubDirTemplate(subDir); name == "" {
+			return "", nil
+		}
+	}
 
+	usesTitle, err := vault.TemplateUsesTitle(name)
 	if err != nil {
-		return time, fmt.Errorf("failed to parse time %s (%s): %w", name, parsedName, err)
+		return "", err
 	}
 
-	return time, nil
+	if usesTitle && title == "" && prompt {
+		if title, err = promptLine(os.Stdin, os.Stdout, "Title: "); err != nil {
+			return "", err
+		}
+	}
+
+	return vault.RenderTemplate(name, notes.NewTemplateData(created, subDir, title))
 }
 
-func CreateFile(path string) (err error) {
-	file, err := os.Create(path)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
+// Combine the body from the flag, the file, and stdin when the source is '-'
+func readNewBody(source, body, bodyFile string) (string, error) {
+	parts := []string{}
+	if body != "" {
+		parts = append(parts, body)
+	}
+
+	if bodyFile != "" {
+		content, err := os.ReadFile(bodyFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read body file %s: %w", bodyFile, err)
+		}
+
+		parts = append(parts, string(content))
+	}
+
+	switch source {
+	case "":
+	case "-":
+		content, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read body from stdin: %w", err)
+		}
+
+		parts = append(parts, string(content))
+	default:
+		return "", fmt.Errorf("%w: '%s'. Use '-' to read the body from stdin", errUnknownSource, source)
 	}
 
-	defer file.Close()
+	return strings.Join(parts, "\n"), nil
+}
 
-	return
+type NewSource struct {
+	Source string `description:"Use '-' to read the note body from stdin" pos:"1"`
 }
 
 func AttachNew(cli *clir.Cli) {
 	newCmd := cli.NewSubCommand("new", "Create a new note")
 
+	source := NewSource{}
+	newCmd.AddFlags(&source)
+
 	syncDir := config.GetSyncDir()
 	newCmd.StringFlag("sync-dir", "Sync Directory", &syncDir)
 
 	subDir := config.GetSubDir()
 	newCmd.StringFlag("sub-dir", "SubDir of Shears Sync directory", &subDir)
 
+	template := ""
+	newCmd.StringFlag("template", "Template in the sync directory's .templates folder. Defaults to the subDir or 'default'", &template)
+
+	title := ""
+	newCmd.StringFlag("title", "Title stored in the note header", &title)
+
+	body := ""
+	newCmd.StringFlag("body", "Text appended to the note body", &body)
+
+	bodyFile := ""
+	newCmd.StringFlag("body-file", "File, such as a saved clipboard, appended to the note body", &bodyFile)
+
 	open := false
-	newCmd.BoolFlag("o", "If set, opens the file in `$VISUAL`", &open)
+	newCmd.BoolFlag("o", "If set, opens the file in `$VISUAL` or `$EDITOR`", &open)
 
 	newCmd.Action(func() error {
-		folderNames, err := ListsubDirs(syncDir)
+		vault, err := openVault(syncDir)
 		if err != nil {
 			return err
 		}
 
-		if !slices.Contains(folderNames, subDir) {
-			return fmt.Errorf(
-				"'%s' is not one of %v subDirs in '%s'. Create the folder if intended",
-				subDir,
-				folderNames,
-				syncDir,
-			)
+		text, err := readNewBody(source.Source, body, bodyFile)
+		if err != nil {
+			return err
 		}
 
-		name := ToTimeName(time.Now()) + ".dj"
+		created := time.Now()
+		interactive := source.Source == "" && body == "" && bodyFile == ""
 
-		path := filepath.Join(syncDir, subDir, name)
-		if err := CreateFile(path); err != nil {
+		content, err := renderNewNote(vault, subDir, template, created, title, interactive)
+		if err != nil {
 			return err
 		}
 
-		if open {
-			visual := os.Getenv("VISUAL")
-			if visual == "" {
-				return errors.New("no value is set for shell environment 'VISUAL'")
-			}
+		if !interactive || title != "" {
+			content = notes.FillNote(content, created, title, text)
+		}
 
-			cmd := exec.Command("open", "-a", visual, path)
-			out, err := cmd.Output()
+		path, err := vault.CreateNoteWithContent(subDir, created, content)
+		if err != nil {
+			return err
+		}
 
-			if err != nil {
-				return fmt.Errorf(
-					"failed to run '%v' with error '%w' and output '%v'",
-					cmd,
-					err,
-					out,
-				)
+		if open {
+			if err := launchEditor(path, 0); err != nil {
+				return err
 			}
 		}
 
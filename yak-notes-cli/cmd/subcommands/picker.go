@@ -0,0 +1,112 @@
+package subcommands
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+)
+
+const pickerLimit = 10
+
+var errNoSelection = errors.New("no note was selected")
+
+func filterNotes(stats []notes.FileStat, query string) []notes.FileStat {
+	if query == "" {
+		return stats
+	}
+
+	query = strings.ToLower(query)
+	matches := []notes.FileStat{}
+
+	for _, stat := range stats {
+		content, err := os.ReadFile(stat.Path)
+		if err != nil {
+			continue
+		}
+
+		haystack := strings.ToLower(stat.SubDir + "/" + stat.Name + "\n" + string(content))
+		if strings.Contains(haystack, query) {
+			matches = append(matches, stat)
+		}
+	}
+
+	return matches
+}
+
+// The title to show after the name of a note or empty when unknown
+func pickerTitle(stat notes.FileStat) string {
+	doc, err := notes.ReadDocument(stat.Path)
+	if err != nil {
+		return ""
+	}
+
+	if title := doc.Title(); title != "" {
+		return "  " + title
+	}
+
+	return ""
+}
+
+// Interactively select a note, which defaults to recent by modified date, then filters based on text input
+func pickNote(vault *notes.Vault, in io.Reader, out io.Writer) (notes.FileStat, error) {
+	recent, err := vault.RecentNotes(0)
+	if err != nil {
+		return notes.FileStat{}, err
+	}
+
+	return pickFrom(recent, in, out)
+}
+
+// Interactively select one of the notes, which are filtered based on text input
+func pickFrom(recent []notes.FileStat, in io.Reader, out io.Writer) (notes.FileStat, error) {
+	scanner := bufio.NewScanner(in)
+	query := ""
+
+	for {
+		candidates := filterNotes(recent, query)
+		if len(candidates) > pickerLimit {
+			candidates = candidates[:pickerLimit]
+		}
+
+		for i, stat := range candidates {
+			fmt.Fprintf(out, "%2d) %s/%s%s\n", i+1, stat.SubDir, stat.Name, pickerTitle(stat))
+		}
+
+		fmt.Fprint(out, "Select a number or type to filter: ")
+
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return notes.FileStat{}, fmt.Errorf("failed to read selection: %w", err)
+			}
+
+			return notes.FileStat{}, errNoSelection
+		}
+
+		input := strings.TrimSpace(scanner.Text())
+		if index, err := strconv.Atoi(input); err == nil && index > 0 && index <= len(candidates) {
+			return candidates[index-1], nil
+		}
+
+		query = input
+	}
+}
+
+// Resolve the note reference or fallback to interactive selection
+func resolveOrPickNote(vault *notes.Vault, ref string) (notes.FileStat, error) {
+	if ref != "" {
+		stat, err := vault.Resolve(ref)
+		if err != nil {
+			return stat, fmt.Errorf("failed to find note: %w", err)
+		}
+
+		return stat, nil
+	}
+
+	return pickNote(vault, os.Stdin, os.Stdout)
+}
@@ -2,50 +2,87 @@ package subcommands
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
-	"strings"
+	"time"
 
-	"github.com/djherbis/times"
 	"github.com/leaanthony/clir"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/config"
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
 )
 
-func readCreationTime(path string) (string, error) {
-	t, err := times.Stat(path)
+type RenameFlags struct {
+	Path    string `description:"Path to the file, or the directory with --all" pos:"1"`
+	SyncDir string `description:"Sync Directory" name:"sync-dir"`
+	All     bool   `description:"If set, rename every note in the directory without a timestamp name" name:"all"`
+	DryRun  bool   `description:"If set, only print the planned renames" name:"dry-run"`
+	Undo    string `description:"Undo log from a previous --all rename to revert" name:"undo"`
+}
+
+func renameAllAction(flags *RenameFlags) error {
+	plan, err := notes.PlanRenameAll(flags.Path)
 	if err != nil {
-		return "", fmt.Errorf("error with specified file (`%s`): %w", path, err)
+		return err
 	}
 
-	return ToTimeName(t.BirthTime()), nil
-}
+	fmt.Print(plan)
 
-func renameFile(path, cTime string) error {
-	basename, _, _ := strings.Cut(filepath.Base(path), ".")
+	if flags.DryRun || len(plan) == 0 {
+		return nil
+	}
 
-	newPath := strings.ReplaceAll(path, basename, cTime)
-	if err := os.Rename(path, newPath); err != nil {
-		return fmt.Errorf("failed to rename file from %s to %s: %w", path, newPath, err)
+	undoLog := notes.UndoLogPath(flags.Path, time.Now())
+	if err := plan.Apply(undoLog); err != nil {
+		return fmt.Errorf("failed to rename notes in %s: %w", flags.Path, err)
 	}
 
-	return nil
-}
+	fmt.Printf("Renamed %d notes. Revert with --undo=%s\n", len(plan), undoLog)
 
-type RenameFlags struct {
-	Path string `description:"Path to the file" pos:"1"`
+	return nil
 }
 
 func renameAction(flags *RenameFlags) (err error) {
-	cTime, err := readCreationTime(flags.Path)
+	if flags.SyncDir == "" {
+		flags.SyncDir = config.GetSyncDir()
+	}
+
+	if flags.Undo != "" {
+		plan, err := notes.UndoRenames(flags.Undo)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Reverted %d renames\n", len(plan))
+
+		return nil
+	}
+
+	if flags.All {
+		return renameAllAction(flags)
+	}
+
+	if flags.DryRun {
+		plan, err := notes.PlanRenames([]string{flags.Path})
+		if err != nil {
+			return err
+		}
+
+		fmt.Print(plan)
+
+		return nil
+	}
+
+	vault, err := openVault(flags.SyncDir)
 	if err != nil {
 		return
 	}
 
-	err = renameFile(flags.Path, cTime)
+	newPath, source, err := vault.RenameNote(flags.Path)
 	if err != nil {
-		return fmt.Errorf("failed to rename file: %w", err)
+		return
 	}
 
-	fmt.Printf("Renamed %s with time %v\n", flags.Path, cTime)
+	fmt.Printf("Renamed %s to %s (from the %s)\n", flags.Path, filepath.Base(newPath), source)
 
 	return
 }
@@ -0,0 +1,110 @@
+package subcommands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/leaanthony/clir"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/config"
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+)
+
+type ShowFlags struct {
+	Note    string `description:"Note to show. Interactively selected when omitted" pos:"1"`
+	SyncDir string `description:"Sync Directory" name:"sync-dir"`
+	Raw     bool   `description:"Print the file without formatting" name:"raw"`
+}
+
+func isTerminal(file *os.File) bool {
+	fi, err := file.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// The pager from `$PAGER`, which defaults to `less -R` to keep the ANSI styles. Returns false for the default
+func pagerArgs() ([]string, bool, error) {
+	value := strings.TrimSpace(os.Getenv("PAGER"))
+	if value == "" {
+		return []string{"less", "-R"}, false, nil
+	}
+
+	args, err := splitCommand(value)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to parse PAGER: %w", err)
+	}
+
+	return args, true, nil
+}
+
+func writeStdout(output string) error {
+	if _, err := io.WriteString(os.Stdout, output); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+
+	return nil
+}
+
+// Write the output through the pager when stdout is a terminal and the pager is set or installed
+func page(output string) error {
+	if !isTerminal(os.Stdout) {
+		return writeStdout(output)
+	}
+
+	args, custom, err := pagerArgs()
+	if err != nil {
+		return err
+	}
+
+	if _, err := exec.LookPath(args[0]); err != nil && !custom {
+		return writeStdout(output)
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(output)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run pager '%s': %w", strings.Join(args, " "), err)
+	}
+
+	return nil
+}
+
+func showAction(flags *ShowFlags) (err error) {
+	if flags.SyncDir == "" {
+		flags.SyncDir = config.GetSyncDir()
+	}
+
+	vault, err := openVault(flags.SyncDir)
+	if err != nil {
+		return
+	}
+
+	stat, err := resolveOrPickNote(vault, flags.Note)
+	if err != nil {
+		return
+	}
+
+	content, err := os.ReadFile(stat.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s: %w", stat.Path, err)
+	}
+
+	if flags.Raw {
+		return page(string(content))
+	}
+
+	doc := notes.ParseDocument(string(content))
+
+	color := isTerminal(os.Stdout) && os.Getenv("NO_COLOR") == ""
+
+	return page(vault.RenderTerminal(stat.Path, doc, color))
+}
+
+func AttachShow(cli *clir.Cli) {
+	cli.NewSubCommandFunction("show", "Render a note for reading in the terminal", showAction)
+}
@@ -1 +1,62 @@
 package subcommands
+
+import (
+	"fmt"
+
+	"github.com/leaanthony/clir"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/config"
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+)
+
+type SplitFlags struct {
+	Note    string `description:"Note to split. Interactively selected when omitted" pos:"1"`
+	SyncDir string `description:"Sync Directory" name:"sync-dir"`
+	Marker  string `description:"Line that separates notes. Top-level headings are used when not found" name:"marker"`
+	DryRun  bool   `description:"If set, only print the planned file changes" name:"dry-run"`
+}
+
+func splitAction(flags *SplitFlags) (err error) {
+	if flags.SyncDir == "" {
+		flags.SyncDir = config.GetSyncDir()
+	}
+
+	if flags.Marker == "" {
+		flags.Marker = notes.DefaultSplitMarker
+	}
+
+	vault, err := openVault(flags.SyncDir)
+	if err != nil {
+		return
+	}
+
+	stat, err := resolveOrPickNote(vault, flags.Note)
+	if err != nil {
+		return
+	}
+
+	plan, err := vault.PlanSplit(stat.Path, flags.Marker)
+	if err != nil {
+		return
+	}
+
+	fmt.Print(vault.FormatPlan(plan))
+
+	if flags.DryRun {
+		return
+	}
+
+	if err = vault.Apply(plan); err != nil {
+		return fmt.Errorf("failed to split %s: %w", stat.Name, err)
+	}
+
+	return
+}
+
+func AttachSplit(cli *clir.Cli) {
+	cli.NewSubCommandFunction(
+		"split",
+		"Split a note into atomic notes at headings or markers",
+		splitAction,
+	)
+}
@@ -1 +1,63 @@
 package subcommands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/leaanthony/clir"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/config"
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+)
+
+type StateFlags struct {
+	State   string `description:"One of Atomic, backlog, queue, in-progress, complete, or not-planned" pos:"1"`
+	Note    string `description:"Note to update. Interactively selected when omitted" pos:"2"`
+	SyncDir string `description:"Sync Directory" name:"sync-dir"`
+}
+
+func stateAction(flags *StateFlags) (err error) {
+	if flags.State == "" {
+		return errors.New("a state is required")
+	}
+
+	state, err := notes.ParseState(flags.State)
+	if err != nil {
+		return
+	}
+
+	if flags.SyncDir == "" {
+		flags.SyncDir = config.GetSyncDir()
+	}
+
+	vault, err := openVault(flags.SyncDir)
+	if err != nil {
+		return
+	}
+
+	stat, err := resolveOrPickNote(vault, flags.Note)
+	if err != nil {
+		return
+	}
+
+	change, err := vault.SetState(stat.Path, state)
+	if err != nil {
+		return
+	}
+
+	fmt.Printf("Set state of %s to %s (was '%s')\n", vault.RelPath(stat.Path), state, change.Previous)
+
+	if change.NextOccurrence != "" {
+		fmt.Printf("Created the next occurrence: %s\n", change.NextOccurrence)
+	}
+
+	return
+}
+
+func AttachState(cli *clir.Cli) {
+	cli.NewSubCommandFunction(
+		"state",
+		"Set the state of a note",
+		stateAction,
+	)
+}
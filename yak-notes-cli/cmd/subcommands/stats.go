@@ -0,0 +1,105 @@
+package subcommands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/leaanthony/clir"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/config"
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+)
+
+var errUnknownOutput = errors.New("unknown output format")
+
+func countsTable(title, column string, counts []notes.Count) string {
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{column, "Notes"})
+
+	for _, count := range counts {
+		t.AppendRow(table.Row{count.Name, count.Count})
+	}
+
+	return title + "\n" + t.Render()
+}
+
+func linksTable(title string, counts []notes.LinkCount) string {
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"Note", "Title", "Inbound", "Outbound"})
+
+	for _, count := range counts {
+		t.AppendRow(table.Row{count.Note, count.Title, count.Inbound, count.Outbound})
+	}
+
+	t.SetColumnConfigs([]table.ColumnConfig{{Name: "Title", WidthMax: 40}})
+
+	return title + "\n" + t.Render()
+}
+
+func summarizeStats(stats notes.VaultStats) string {
+	activity := table.NewWriter()
+	activity.AppendHeader(table.Row{"Week", "Created", "Modified", "Words Written"})
+
+	written := 0
+
+	for _, week := range stats.Weeks {
+		activity.AppendRow(table.Row{week.Week, week.Created, week.Modified, week.Words})
+		written += week.Words
+	}
+
+	activity.AppendFooter(table.Row{"Total", stats.Notes, stats.Notes, written})
+
+	return strings.Join([]string{
+		countsTable("Notes by subDir", "subDir", stats.SubDirs),
+		countsTable("Notes by State", "State", stats.States),
+		"Weekly Activity\n" + activity.Render(),
+		linksTable("Most Linked", stats.MostLinked),
+		linksTable("Most Orphaned", stats.MostOrphaned),
+	}, "\n\n")
+}
+
+func AttachStats(cli *clir.Cli) {
+	statsCmd := cli.NewSubCommand("stats", "Summarize the notes, the weekly writing activity, and the links")
+
+	syncDir := config.GetSyncDir()
+	statsCmd.StringFlag("sync-dir", "Sync Directory", &syncDir)
+
+	top := 10
+	statsCmd.IntFlag("top", "Number of the most linked and orphaned notes to list", &top)
+
+	outputFormat := "text"
+	statsCmd.StringFlag("output", "Output format. One of text or json", &outputFormat)
+
+	statsCmd.Action(func() (err error) {
+		if outputFormat != "text" && outputFormat != "json" {
+			return fmt.Errorf("%w: %s", errUnknownOutput, outputFormat)
+		}
+
+		vault, err := openVault(syncDir)
+		if err != nil {
+			return
+		}
+
+		stats, err := vault.Stats(top)
+		if err != nil {
+			return
+		}
+
+		if outputFormat == "text" {
+			fmt.Println(summarizeStats(stats))
+			return
+		}
+
+		output, err := json.MarshalIndent(stats, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode stats: %w", err)
+		}
+
+		fmt.Println(string(output))
+
+		return
+	})
+}
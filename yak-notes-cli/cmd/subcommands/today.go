@@ -0,0 +1,64 @@
+package subcommands
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/leaanthony/clir"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/config"
+)
+
+type TodayFlags struct {
+	SyncDir string `description:"Sync Directory" name:"sync-dir"`
+	SubDir  string `description:"SubDir for journal notes" name:"sub-dir"`
+	Date    string `description:"Journal date as YYYY-MM-DD. Defaults to today" name:"date"`
+	Print   bool   `description:"If set, prints the path without opening the file in $VISUAL or $EDITOR" name:"print"`
+}
+
+func todayAction(flags *TodayFlags) (err error) {
+	if flags.SyncDir == "" {
+		flags.SyncDir = config.GetSyncDir()
+	}
+
+	if flags.SubDir == "" {
+		flags.SubDir = config.GetJournalSubDir()
+	}
+
+	date := time.Now()
+	if flags.Date != "" {
+		if date, err = time.Parse(time.DateOnly, flags.Date); err != nil {
+			return fmt.Errorf("failed to parse date '%s': %w", flags.Date, err)
+		}
+	}
+
+	vault, err := openVault(flags.SyncDir)
+	if err != nil {
+		return
+	}
+
+	path, created, err := vault.OpenJournal(flags.SubDir, date)
+	if err != nil {
+		return
+	}
+
+	// Only the path is written to stdout, so that scripts can capture it
+	if created {
+		log.Printf("Created journal for %s\n", date.Format(time.DateOnly))
+	}
+
+	if !flags.Print {
+		if err := launchEditor(path, 0); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println(path)
+
+	return nil
+}
+
+func AttachToday(cli *clir.Cli) {
+	cli.NewSubCommandFunction("today", "Open or create the daily journal note", todayAction)
+}
@@ -0,0 +1,22 @@
+package subcommands_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/subcommands"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachAgenda(t *testing.T) {
+	syncDir, tmpTestSubDir := resetTmpSyncDir(t, "agenda")
+	content := ": state=queue\\\n: hard-deadline=" + time.Now().Format(time.DateOnly) + "\\\n\n- [x] Subtask\n- [ ] Other\n"
+	require.NoError(t, os.WriteFile(filepath.Join(tmpTestSubDir, "2024-01-01T00_00_00Z.dj"), []byte(content), 0o600))
+
+	cli := initTestCli()
+	subcommands.AttachAgenda(cli)
+	err := cli.Run("agenda", "-sync-dir", syncDir, "-days", "3")
+	require.NoError(t, err)
+}
@@ -0,0 +1,130 @@
+package subcommands_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/config"
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/subcommands"
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachTrashRestore(t *testing.T) {
+	syncDir, tmpTestSubDir := resetTmpSyncDir(t, "trash")
+	path := filepath.Join(tmpTestSubDir, "2024-01-01T00_00_00Z.dj")
+	require.NoError(t, os.WriteFile(path, []byte("Body\n"), 0o600))
+
+	cli := initTestCli()
+	subcommands.AttachTrash(cli)
+	err := cli.Run("trash", path, "-sync-dir", syncDir)
+	require.NoError(t, err)
+
+	_, err = os.Stat(filepath.Join(syncDir, notes.TrashDir, "notes", "2024-01-01T00_00_00Z.dj"))
+	require.NoError(t, err)
+
+	cli = initTestCli()
+	subcommands.AttachTrash(cli)
+	err = cli.Run("trash", "-purge", "-sync-dir", syncDir)
+	require.NoError(t, err)
+
+	setStdin(t, "1\n")
+
+	cli = initTestCli()
+	subcommands.AttachRestore(cli)
+	err = cli.Run("restore", "-sync-dir", syncDir)
+	require.NoError(t, err)
+
+	_, err = os.Stat(path)
+	require.NoError(t, err)
+}
+
+func TestAttachTrashPurgeAll(t *testing.T) {
+	syncDir, tmpTestSubDir := resetTmpSyncDir(t, "trash-purge")
+	path := filepath.Join(tmpTestSubDir, "2024-01-01T00_00_00Z.dj")
+	require.NoError(t, os.WriteFile(path, []byte("Body\n"), 0o600))
+
+	sourcePath := filepath.Join(tmpTestSubDir, "2024-01-02T00_00_00Z.dj")
+	require.NoError(t, os.WriteFile(sourcePath, []byte("See [body](2024-01-01T00_00_00Z.dj)\n"), 0o600))
+
+	cli := initTestCli()
+	subcommands.AttachTrash(cli)
+	err := cli.Run("trash", path, "-sync-dir", syncDir, "-retention-days", "0")
+	require.NoError(t, err)
+
+	_, err = os.Stat(filepath.Join(syncDir, notes.TrashDir, "notes", "2024-01-01T00_00_00Z.dj"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	content, err := os.ReadFile(sourcePath)
+	require.NoError(t, err)
+	assert.Equal(t, "See body\n", string(content))
+}
+
+func TestAttachArchive(t *testing.T) {
+	syncDir, tmpTestSubDir := resetTmpSyncDir(t, "archive")
+	path := filepath.Join(tmpTestSubDir, "2024-01-01T00_00_00Z.dj")
+	require.NoError(t, os.WriteFile(path, []byte("Body\n"), 0o600))
+
+	cli := initTestCli()
+	subcommands.AttachArchive(cli)
+	err := cli.Run("archive", path, "-sync-dir", syncDir)
+	require.NoError(t, err)
+
+	cli = initTestCli()
+	subcommands.AttachRestore(cli)
+	err = cli.Run("restore", "2024-01-01T00_00_00Z", "-sync-dir", syncDir)
+	require.NoError(t, err)
+
+	_, err = os.Stat(path)
+	require.NoError(t, err)
+}
+
+func TestAttachArchiveIndex(t *testing.T) {
+	syncDir, tmpTestSubDir := resetTmpSyncDir(t, "archive-index")
+	setYakShearsDir(t)
+
+	path := filepath.Join(tmpTestSubDir, "2024-01-01T00_00_00Z.dj")
+	require.NoError(t, os.WriteFile(path, []byte("[other](2024-01-02T00_00_00Z.dj)\n"), 0o600))
+
+	vault, err := notes.NewVault(syncDir)
+	require.NoError(t, err)
+
+	vault.MigrationsDir = config.GetMigrationsDir()
+
+	db, err := vault.OpenIndex()
+	require.NoError(t, err)
+	require.NoError(t, vault.Reindex(db))
+	require.NoError(t, db.Close())
+
+	countRows := func() []int {
+		db, err := vault.OpenIndex()
+		require.NoError(t, err)
+
+		defer db.Close()
+
+		counts := []int{}
+
+		for _, table := range []string{"note", "embedding", "link"} {
+			var count int
+			require.NoError(t, db.Get(&count, "SELECT count(*) FROM "+table))
+
+			counts = append(counts, count)
+		}
+
+		return counts
+	}
+
+	require.Equal(t, []int{1, 1, 1}, countRows())
+
+	cli := initTestCli()
+	subcommands.AttachArchive(cli)
+	require.NoError(t, cli.Run("archive", path, "-sync-dir", syncDir))
+	assert.Equal(t, []int{0, 0, 0}, countRows())
+
+	cli = initTestCli()
+	subcommands.AttachRestore(cli)
+	require.NoError(t, cli.Run("restore", "2024-01-01T00_00_00Z", "-sync-dir", syncDir))
+	assert.Equal(t, []int{1, 1, 1}, countRows())
+}
@@ -0,0 +1,36 @@
+package subcommands_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/subcommands"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachCheckLinks(t *testing.T) {
+	syncDir, tmpTestSubDir := resetTmpSyncDir(t, "checkLinks")
+	sourcePath := filepath.Join(tmpTestSubDir, "2024-01-01T00_00_00Z.dj")
+	require.NoError(t, os.WriteFile(sourcePath, []byte("[old](2024-02-01T00_00_00Z.dj)\n"), 0o600))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(tmpTestSubDir, "2024-03-01T00_00_00Z.dj"),
+		[]byte(": merged-from=notes/2024-02-01T00_00_00Z.dj\\\n"),
+		0o600,
+	))
+
+	cli := initTestCli()
+	subcommands.AttachCheckLinks(cli)
+	err := cli.Run("check-links", "-sync-dir", syncDir)
+	require.Error(t, err)
+
+	cli = initTestCli()
+	subcommands.AttachCheckLinks(cli)
+	err = cli.Run("check-links", "-sync-dir", syncDir, "-fix")
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(sourcePath)
+	require.NoError(t, err)
+	assert.Equal(t, "[old](2024-03-01T00_00_00Z.dj)\n", string(content))
+}
@@ -0,0 +1,37 @@
+package subcommands_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/subcommands"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachDoctor(t *testing.T) {
+	syncDir, tmpTestSubDir := resetTmpSyncDir(t, "doctor")
+	notePath := filepath.Join(tmpTestSubDir, "2024-01-01T00_00_00Z.dj")
+	require.NoError(t, os.WriteFile(notePath, []byte(": state=backlog\\ \n: links=a.dj\\\n\nBody\n"), 0o600))
+
+	cli := initTestCli()
+	subcommands.AttachDoctor(cli)
+
+	var err error
+
+	output := captureStdout(t, func() { err = cli.Run("doctor", "-sync-dir", syncDir) })
+	require.Error(t, err)
+	assert.Contains(t, output, "error notes/2024-01-01T00_00_00Z.dj: header line 1 has trailing whitespace")
+
+	cli = initTestCli()
+	subcommands.AttachDoctor(cli)
+
+	output = captureStdout(t, func() { err = cli.Run("doctor", "-sync-dir", syncDir, "-fix") })
+	require.NoError(t, err)
+	assert.Contains(t, output, "Fixed notes/2024-01-01T00_00_00Z.dj: remove the trailing whitespace")
+
+	content, err := os.ReadFile(notePath)
+	require.NoError(t, err)
+	assert.Equal(t, ": state=backlog\\\n: links=a.dj\\\n\nBody\n", string(content))
+}
@@ -0,0 +1,108 @@
+package subcommands_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/subcommands"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Write a script with the name in a temporary directory that records the arguments and return the path of the script
+// and the recording
+func writeTestEditor(t *testing.T, name string) (string, string) {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the test editor is a shell script")
+	}
+
+	dir := t.TempDir()
+	output := filepath.Join(dir, "args.txt")
+	script := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\necho \"$@\" > '"+output+"'\n"), 0o700))
+
+	return script, output
+}
+
+// Set $VISUAL to a script that records the arguments and return the path of the recording
+func setTestEditor(t *testing.T, extraArgs string) string {
+	t.Helper()
+
+	script, output := writeTestEditor(t, "test editor.sh")
+	t.Setenv("VISUAL", "'"+script+"' "+extraArgs)
+	t.Setenv("EDITOR", "")
+
+	return output
+}
+
+func TestAttachEdit(t *testing.T) {
+	output := setTestEditor(t, "--wait")
+	tmpTestSubDir := resetTmpTestDir(t, "edit")
+	path := filepath.Join(tmpTestSubDir, "2024-03-01T00_00_00Z.dj")
+	require.NoError(t, os.WriteFile(path, []byte("Body\n"), 0o600))
+
+	cli := initTestCli()
+	subcommands.AttachEdit(cli)
+	err := cli.Run("edit", "edit/2024-03-01T00_00_00Z.dj", "-sync-dir", filepath.Dir(tmpTestSubDir), "-line", "3")
+	require.NoError(t, err)
+
+	args, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, "--wait +3 "+path+"\n", string(args))
+}
+
+func TestAttachEditPicker(t *testing.T) {
+	output := setTestEditor(t, "")
+	syncDir, tmpTestSubDir := resetTmpSyncDir(t, "edit-picker")
+	path := filepath.Join(tmpTestSubDir, "2024-03-02T00_00_00Z.dj")
+	require.NoError(t, os.WriteFile(path, []byte("Body\n"), 0o600))
+
+	setStdin(t, "1\n")
+
+	cli := initTestCli()
+	subcommands.AttachEdit(cli)
+	err := cli.Run("edit", "-sync-dir", syncDir)
+	require.NoError(t, err)
+
+	args, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, path+"\n", string(args))
+}
+
+// A Windows path like `C:\Program Files\...\code.exe --wait` keeps the backslashes and the unquoted spaces, which are
+// part of the file name on other platforms
+func TestAttachEditWindowsPath(t *testing.T) {
+	script, output := writeTestEditor(t, `Program Files\Test Editor\editor.sh`)
+	t.Setenv("VISUAL", script+" --wait")
+	t.Setenv("EDITOR", "")
+
+	tmpTestSubDir := resetTmpTestDir(t, "edit")
+	path := filepath.Join(tmpTestSubDir, "2024-03-04T00_00_00Z.dj")
+	require.NoError(t, os.WriteFile(path, []byte("Body\n"), 0o600))
+
+	cli := initTestCli()
+	subcommands.AttachEdit(cli)
+	err := cli.Run("edit", path, "-sync-dir", filepath.Dir(tmpTestSubDir))
+	require.NoError(t, err)
+
+	args, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, "--wait "+path+"\n", string(args))
+}
+
+func TestAttachEditInvalidEditor(t *testing.T) {
+	t.Setenv("VISUAL", "'unclosed")
+
+	tmpTestSubDir := resetTmpTestDir(t, "edit")
+	path := filepath.Join(tmpTestSubDir, "2024-03-03T00_00_00Z.dj")
+	require.NoError(t, os.WriteFile(path, []byte("Body\n"), 0o600))
+
+	cli := initTestCli()
+	subcommands.AttachEdit(cli)
+	err := cli.Run("edit", path, "-sync-dir", filepath.Dir(tmpTestSubDir))
+	require.Error(t, err)
+}
@@ -0,0 +1,47 @@
+package subcommands_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/subcommands"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachFmt(t *testing.T) {
+	syncDir, tmpTestSubDir := resetTmpSyncDir(t, "fmt")
+	path := filepath.Join(tmpTestSubDir, "2024-01-01T00_00_00Z.dj")
+	formattedPath := filepath.Join(tmpTestSubDir, "2024-02-01T00_00_00Z.dj")
+
+	require.NoError(t, os.WriteFile(path, []byte(": state=queue\\\n#  Title\n\n* item\n"), 0o600))
+	require.NoError(t, os.WriteFile(formattedPath, []byte("# Done\n"), 0o600))
+
+	var err error
+
+	output := captureStdout(t, func() {
+		cli := initTestCli()
+		subcommands.AttachFmt(cli)
+		err = cli.Run("fmt", "-sync-dir", syncDir, "-check")
+	})
+	require.Error(t, err)
+	assert.Contains(t, output, "notes/2024-01-01T00_00_00Z.dj:4: use '-' instead of '*' for the bullet\n")
+	assert.NotContains(t, output, "2024-02-01T00_00_00Z.dj")
+
+	output = captureStdout(t, func() {
+		cli := initTestCli()
+		subcommands.AttachFmt(cli)
+		err = cli.Run("fmt", "-sync-dir", syncDir, path)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Formatted notes/2024-01-01T00_00_00Z.dj\n", output)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, ": state=queue\\\n\n# Title\n\n- item\n", string(content))
+
+	cli := initTestCli()
+	subcommands.AttachFmt(cli)
+	require.NoError(t, cli.Run("fmt", "-sync-dir", syncDir, "-check"))
+}
@@ -0,0 +1,35 @@
+package subcommands_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/subcommands"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachLink(t *testing.T) {
+	syncDir, tmpTestSubDir := resetTmpSyncDir(t, "link")
+	setYakShearsDir(t)
+
+	fromPath := filepath.Join(tmpTestSubDir, "2024-01-01T00_00_00Z.dj")
+	toPath := filepath.Join(tmpTestSubDir, "2024-02-01T00_00_00Z.dj")
+	require.NoError(t, os.WriteFile(fromPath, []byte("From\n"), 0o600))
+	require.NoError(t, os.WriteFile(toPath, []byte("To\n"), 0o600))
+
+	cli := initTestCli()
+	subcommands.AttachLink(cli)
+	err := cli.Run("link", fromPath, toPath, "-sync-dir", syncDir)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(fromPath)
+	require.NoError(t, err)
+	assert.Equal(t, ": links=notes/2024-02-01T00_00_00Z.dj\\\n\nFrom\n", string(content))
+
+	cli = initTestCli()
+	subcommands.AttachBacklinks(cli)
+	err = cli.Run("backlinks", toPath, "-sync-dir", syncDir)
+	require.NoError(t, err)
+}
@@ -1,6 +1,7 @@
 package subcommands_test
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -18,3 +19,14 @@ func TestAttachList(t *testing.T) {
 	err = cli.Run("list", "-sync-dir", filepath.Dir(tmpTestSubDir))
 	require.NoError(t, err)
 }
+
+func TestAttachListProgress(t *testing.T) {
+	syncDir, subDir := resetTmpSyncDir(t, "list-progress")
+	content := ": state=complete\\\n\n- [x] Done\n- [ ] Open\n"
+	require.NoError(t, os.WriteFile(filepath.Join(subDir, "2024-01-01T00_00_00Z.dj"), []byte(content), 0o600))
+
+	cli := initTestCli()
+	subcommands.AttachList(cli)
+	err := cli.Run("list", "-sync-dir", syncDir)
+	require.NoError(t, err)
+}
@@ -0,0 +1,40 @@
+package subcommands_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/subcommands"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachMerge(t *testing.T) {
+	tmpTestSubDir := resetTmpTestDir(t, "merge")
+	fromPath := filepath.Join(tmpTestSubDir, "2024-01-01T00_00_00Z.dj")
+	toPath := filepath.Join(tmpTestSubDir, "2024-02-01T00_00_00Z.dj")
+	require.NoError(t, os.WriteFile(fromPath, []byte("From\n"), 0o600))
+	require.NoError(t, os.WriteFile(toPath, []byte("To\n"), 0o600))
+
+	syncDir := filepath.Dir(tmpTestSubDir)
+
+	cli := initTestCli()
+	subcommands.AttachMerge(cli)
+	err := cli.Run("merge", fromPath, toPath, "-sync-dir", syncDir, "-dry-run")
+	require.NoError(t, err)
+
+	_, err = os.Stat(fromPath)
+	require.NoError(t, err)
+
+	cli = initTestCli()
+	subcommands.AttachMerge(cli)
+	err = cli.Run("merge", fromPath, toPath, "-sync-dir", syncDir)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(toPath)
+	require.NoError(t, err)
+	assert.Equal(t, ": merged-from=merge/2024-01-01T00_00_00Z.dj\\\n\nTo\n\nFrom\n", string(content))
+
+	matchCreatedFile(tmpTestSubDir, "2024-02-01", t)
+}
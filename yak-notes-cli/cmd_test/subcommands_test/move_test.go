@@ -0,0 +1,58 @@
+package subcommands_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/config"
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/subcommands"
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachMove(t *testing.T) {
+	syncDir, tmpTestSubDir := resetTmpSyncDir(t, "move")
+	setYakShearsDir(t)
+	require.NoError(t, os.Mkdir(filepath.Join(syncDir, "archive"), os.ModePerm))
+
+	path := filepath.Join(tmpTestSubDir, "2024-01-01T00_00_00Z.dj")
+	require.NoError(t, os.WriteFile(path, []byte("Body\n"), 0o600))
+
+	vault, err := notes.NewVault(syncDir)
+	require.NoError(t, err)
+
+	vault.MigrationsDir = config.GetMigrationsDir()
+
+	db, err := vault.OpenIndex()
+	require.NoError(t, err)
+	require.NoError(t, vault.Reindex(db))
+	require.NoError(t, db.Close())
+
+	cli := initTestCli()
+	subcommands.AttachMove(cli)
+	err = cli.Run("move", path, "archive", "-sync-dir", syncDir)
+	require.NoError(t, err)
+
+	header := readTestHeader(t, filepath.Join(syncDir, "archive", "2024-01-01T00_00_00Z.dj"))
+	assert.Equal(t, []string{"notes/2024-01-01T00_00_00Z.dj"}, header.GetList(notes.KeyMovedFrom))
+
+	db, err = vault.OpenIndex()
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	var subDir string
+	require.NoError(t, db.Get(&subDir, "SELECT sub_dir FROM note WHERE filename = '2024-01-01T00_00_00Z.dj'"))
+	assert.Equal(t, "archive", subDir)
+}
+
+func TestAttachMoveMissingSubDir(t *testing.T) {
+	syncDir, _ := resetTmpSyncDir(t, "move")
+
+	cli := initTestCli()
+	subcommands.AttachMove(cli)
+	err := cli.Run("move", "2024-01-01T00_00_00Z.dj", "-sync-dir", syncDir)
+	require.Error(t, err)
+}
@@ -1,32 +1,24 @@
 package subcommands_test
 
 import (
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/subcommands"
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-func TestTimeName(t *testing.T) {
-	now := time.Now()
-	name := subcommands.ToTimeName(now)
-
-	restored, err := subcommands.FromTimeName(name)
-
-	require.NoError(t, err)
-	assert.Equal(t, now.UTC().Format(time.RFC3339), restored.Format(time.RFC3339), name)
-}
-
 func TestAttachNew(t *testing.T) {
 	var err error
 
 	tmpTestSubDir := resetTmpTestDir(t, "new")
 
-	baseCTime, _, _ := strings.Cut(subcommands.ToTimeName(time.Now()), "T")
+	baseCTime, _, _ := strings.Cut(notes.ToTimeName(time.Now()), "T")
 
 	cli := initTestCli()
 	subcommands.AttachNew(cli)
@@ -35,3 +27,77 @@ func TestAttachNew(t *testing.T) {
 
 	matchCreatedFile(tmpTestSubDir, baseCTime, t)
 }
+
+func TestAttachNewTemplate(t *testing.T) {
+	syncDir, subDir := resetTmpSyncDir(t, "new-template")
+	templatesDir := filepath.Join(syncDir, notes.TemplatesDir)
+	require.NoError(t, os.Mkdir(templatesDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(templatesDir, "meeting.dj"), []byte(": state=queue\\\n\n# {{.Title}}\n"), 0o600))
+
+	setStdin(t, "Standup\n")
+
+	cli := initTestCli()
+	subcommands.AttachNew(cli)
+	err := cli.Run("new", "-sync-dir", syncDir, "-sub-dir", "notes", "-template", "meeting")
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(subDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	content, err := os.ReadFile(filepath.Join(subDir, entries[0].Name()))
+	require.NoError(t, err)
+	assert.Equal(t, ": state=queue\\\n\n# Standup\n", string(content))
+}
+
+func readOnlyNote(t *testing.T, subDir string) notes.Document {
+	t.Helper()
+
+	entries, err := os.ReadDir(subDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	doc, err := notes.ReadDocument(filepath.Join(subDir, entries[0].Name()))
+	require.NoError(t, err)
+
+	return doc
+}
+
+func TestAttachNewTitleBody(t *testing.T) {
+	syncDir, subDir := resetTmpSyncDir(t, "new-body")
+
+	cli := initTestCli()
+	subcommands.AttachNew(cli)
+	err := cli.Run("new", "-sync-dir", syncDir, "-sub-dir", "notes", "-title", "Idea", "-body", "Write it down")
+	require.NoError(t, err)
+
+	doc := readOnlyNote(t, subDir)
+	name, _ := doc.Header.Get(notes.KeyName)
+	assert.Equal(t, "Idea", name)
+	assert.Equal(t, []string{notes.KeyCreationDate, notes.KeyName}, doc.Header.Keys())
+	assert.Equal(t, "Write it down\n", doc.Body)
+}
+
+func TestAttachNewStdin(t *testing.T) {
+	syncDir, subDir := resetTmpSyncDir(t, "new-stdin")
+
+	setStdin(t, "Piped\ncontent\n")
+
+	cli := initTestCli()
+	subcommands.AttachNew(cli)
+	err := cli.Run("new", "-", "-sync-dir", syncDir, "-sub-dir", "notes")
+	require.NoError(t, err)
+
+	doc := readOnlyNote(t, subDir)
+	assert.Equal(t, []string{notes.KeyCreationDate}, doc.Header.Keys())
+	assert.Equal(t, "Piped\ncontent\n", doc.Body)
+}
+
+func TestAttachNewUnknownSource(t *testing.T) {
+	syncDir, _ := resetTmpSyncDir(t, "new-stdin")
+
+	cli := initTestCli()
+	subcommands.AttachNew(cli)
+	err := cli.Run("new", "clipboard", "-sync-dir", syncDir, "-sub-dir", "notes")
+	require.Error(t, err)
+}
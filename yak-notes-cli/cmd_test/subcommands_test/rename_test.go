@@ -1,12 +1,14 @@
 package subcommands_test
 
 import (
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/subcommands"
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
 	"github.com/stretchr/testify/require"
 )
 
@@ -15,9 +17,9 @@ func TestAttachRename(t *testing.T) {
 
 	tmpTestSubDir := resetTmpTestDir(t, "rename")
 
-	baseCTime, _, _ := strings.Cut(subcommands.ToTimeName(time.Now()), "T")
+	baseCTime, _, _ := strings.Cut(notes.ToTimeName(time.Now()), "T")
 	pathSrc := filepath.Join(tmpTestSubDir, "test-note.ext")
-	err = subcommands.CreateFile(pathSrc)
+	err = notes.CreateFile(pathSrc)
 	require.NoError(t, err)
 
 	cli := initTestCli()
@@ -27,3 +29,37 @@ func TestAttachRename(t *testing.T) {
 
 	matchCreatedFile(tmpTestSubDir, baseCTime, t)
 }
+
+func TestAttachRenameAll(t *testing.T) {
+	tmpTestSubDir := resetTmpTestDir(t, "rename-all")
+	pathSrc := filepath.Join(tmpTestSubDir, "rename-all.dj")
+	require.NoError(t, os.WriteFile(pathSrc, []byte(": creation_date=2020-05-06T07:08:09Z\\\n"), 0o600))
+
+	cli := initTestCli()
+	subcommands.AttachRename(cli)
+	err := cli.Run("rename", tmpTestSubDir, "-all", "-dry-run")
+	require.NoError(t, err)
+
+	_, err = os.Stat(pathSrc)
+	require.NoError(t, err, "a dry-run must not rename files")
+
+	cli = initTestCli()
+	subcommands.AttachRename(cli)
+	err = cli.Run("rename", tmpTestSubDir, "-all")
+	require.NoError(t, err)
+
+	_, err = os.Stat(filepath.Join(tmpTestSubDir, "2020-05-06T07_08_09Z.dj"))
+	require.NoError(t, err)
+
+	logs, err := filepath.Glob(filepath.Join(tmpTestSubDir, ".rename-undo-*.json"))
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+
+	cli = initTestCli()
+	subcommands.AttachRename(cli)
+	err = cli.Run("rename", "-undo", logs[0])
+	require.NoError(t, err)
+
+	_, err = os.Stat(pathSrc)
+	require.NoError(t, err)
+}
@@ -0,0 +1,34 @@
+package subcommands_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/subcommands"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachShow(t *testing.T) {
+	syncDir, subDir := resetTmpSyncDir(t, "show")
+	content := ": state=queue\\\n\n# Plan\n\n- [ ] *First* step\n"
+	require.NoError(t, os.WriteFile(filepath.Join(subDir, "2024-04-01T00_00_00Z.dj"), []byte(content), 0o600))
+
+	for _, tc := range []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{}, "state: queue\n\nPlan\n\n☐ First step\n"},
+		{[]string{"-raw"}, content},
+	} {
+		cli := initTestCli()
+		subcommands.AttachShow(cli)
+
+		output := captureStdout(t, func() {
+			args := append([]string{"show", "2024-04-01T00_00_00Z.dj", "-sync-dir", syncDir}, tc.args...)
+			require.NoError(t, cli.Run(args...))
+		})
+		assert.Equal(t, tc.expected, output)
+	}
+}
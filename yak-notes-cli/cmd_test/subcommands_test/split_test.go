@@ -0,0 +1,26 @@
+package subcommands_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/subcommands"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachSplit(t *testing.T) {
+	syncDir, tmpTestSubDir := resetTmpSyncDir(t, "split")
+	path := filepath.Join(tmpTestSubDir, "2024-01-01T00_00_00Z.dj")
+	require.NoError(t, os.WriteFile(path, []byte("# One\n\nA\n\n# Two\n\nB\n"), 0o600))
+
+	cli := initTestCli()
+	subcommands.AttachSplit(cli)
+	err := cli.Run("split", path, "-sync-dir", syncDir)
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(tmpTestSubDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 3)
+}
@@ -0,0 +1,99 @@
+package subcommands_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/subcommands"
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readTestHeader(t *testing.T, path string) notes.Header {
+	t.Helper()
+
+	doc, err := notes.ReadDocument(path)
+	require.NoError(t, err)
+
+	return doc.Header
+}
+
+func TestAttachState(t *testing.T) {
+	var err error
+
+	tmpTestSubDir := resetTmpTestDir(t, "state")
+	path := filepath.Join(tmpTestSubDir, "2024-01-02T03_04_05Z.dj")
+	require.NoError(t, os.WriteFile(path, []byte("Body\n"), 0o600))
+
+	cli := initTestCli()
+	subcommands.AttachState(cli)
+	err = cli.Run("state", "queue", "state/2024-01-02T03_04_05Z", "-sync-dir", filepath.Dir(tmpTestSubDir))
+	require.NoError(t, err)
+
+	header := readTestHeader(t, path)
+	state, err := header.State()
+	require.NoError(t, err)
+	assert.Equal(t, notes.StateQueue, state)
+
+	_, ok, err := header.GetTime(notes.KeyStateChanged)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestAttachStateInvalid(t *testing.T) {
+	tmpTestSubDir := resetTmpTestDir(t, "state")
+
+	cli := initTestCli()
+	subcommands.AttachState(cli)
+	err := cli.Run("state", "on-hold", "missing.dj", "-sync-dir", filepath.Dir(tmpTestSubDir))
+	require.ErrorIs(t, err, notes.ErrInvalidState)
+}
+
+func TestAttachStatePicker(t *testing.T) {
+	tmpTestSubDir := resetTmpTestDir(t, "state")
+	path := filepath.Join(tmpTestSubDir, "2024-01-02T03_04_05Z.dj")
+	require.NoError(t, os.WriteFile(path, []byte("Needle\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpTestSubDir, "2024-02-02T03_04_05Z.dj"), []byte("Hay\n"), 0o600))
+
+	setStdin(t, "needle\n1\n")
+
+	cli := initTestCli()
+	subcommands.AttachState(cli)
+	err := cli.Run("state", "Atomic", "-sync-dir", filepath.Dir(tmpTestSubDir))
+	require.NoError(t, err)
+
+	header := readTestHeader(t, path)
+	state, err := header.State()
+	require.NoError(t, err)
+	assert.Equal(t, notes.StateAtomic, state)
+}
+
+func TestAttachStateRepeat(t *testing.T) {
+	syncDir, subDir := resetTmpSyncDir(t, "state-repeat")
+	path := filepath.Join(subDir, "2024-01-02T03_04_05Z.dj")
+	today := time.Now()
+	content := ": repeat=daily\\\n: start-date=" + today.Format(time.DateOnly) + "\\\n\n- [x] Stretch\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	cli := initTestCli()
+	subcommands.AttachState(cli)
+	err := cli.Run("state", "complete", path, "-sync-dir", syncDir)
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(subDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	for _, entry := range entries {
+		if entry.Name() == filepath.Base(path) {
+			continue
+		}
+
+		header := readTestHeader(t, filepath.Join(subDir, entry.Name()))
+		start, _ := header.Get(notes.KeyStartDate)
+		assert.Equal(t, today.AddDate(0, 0, 1).Format(time.DateOnly), start)
+	}
+}
@@ -0,0 +1,44 @@
+package subcommands_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/subcommands"
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachStats(t *testing.T) {
+	syncDir, tmpTestSubDir := resetTmpSyncDir(t, "stats")
+	require.NoError(t, os.WriteFile(
+		filepath.Join(tmpTestSubDir, "2024-01-01T00_00_00Z.dj"), []byte(": state=queue\\\n\nSome words\n"), 0o600,
+	))
+
+	cli := initTestCli()
+	subcommands.AttachStats(cli)
+
+	var err error
+
+	output := captureStdout(t, func() { err = cli.Run("stats", "-sync-dir", syncDir) })
+	require.NoError(t, err)
+	assert.Contains(t, output, "Weekly Activity")
+
+	cli = initTestCli()
+	subcommands.AttachStats(cli)
+
+	output = captureStdout(t, func() { err = cli.Run("stats", "-sync-dir", syncDir, "-output", "json") })
+	require.NoError(t, err)
+
+	var stats notes.VaultStats
+	require.NoError(t, json.Unmarshal([]byte(output), &stats))
+	assert.Equal(t, 1, stats.Notes)
+	assert.Equal(t, 2, stats.Words)
+
+	cli = initTestCli()
+	subcommands.AttachStats(cli)
+	require.Error(t, cli.Run("stats", "-sync-dir", syncDir, "-output", "yaml"))
+}
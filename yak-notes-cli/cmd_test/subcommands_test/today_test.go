@@ -0,0 +1,65 @@
+package subcommands_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/subcommands"
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachToday(t *testing.T) {
+	output := setTestEditor(t, "")
+	syncDir, subDir := resetTmpSyncDir(t, "today")
+
+	for range 2 {
+		cli := initTestCli()
+		subcommands.AttachToday(cli)
+		err := cli.Run("today", "-sync-dir", syncDir, "-sub-dir", "notes", "-date", "2024-05-10")
+		require.NoError(t, err)
+	}
+
+	entries, err := os.ReadDir(subDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	path := filepath.Join(subDir, entries[0].Name())
+	header := readTestHeader(t, path)
+	date, _ := header.Get(notes.KeyJournalDate)
+	assert.Equal(t, "2024-05-10", date)
+
+	args, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, path+"\n", string(args))
+}
+
+// With -print, only the path is written to stdout and the editor isn't opened
+func TestAttachTodayPrint(t *testing.T) {
+	output := setTestEditor(t, "")
+	syncDir, subDir := resetTmpSyncDir(t, "today")
+
+	stdout := captureStdout(t, func() {
+		cli := initTestCli()
+		subcommands.AttachToday(cli)
+		err := cli.Run("today", "-sync-dir", syncDir, "-sub-dir", "notes", "-date", "2024-05-10", "-print")
+		require.NoError(t, err)
+	})
+
+	entries, err := os.ReadDir(subDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, filepath.Join(subDir, entries[0].Name())+"\n", stdout)
+	assert.NoFileExists(t, output)
+}
+
+func TestAttachTodayInvalidDate(t *testing.T) {
+	syncDir, _ := resetTmpSyncDir(t, "today")
+
+	cli := initTestCli()
+	subcommands.AttachToday(cli)
+	err := cli.Run("today", "-sync-dir", syncDir, "-sub-dir", "notes", "-date", "tomorrow")
+	require.Error(t, err)
+}
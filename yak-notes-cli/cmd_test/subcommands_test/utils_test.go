@@ -64,3 +64,57 @@ func matchCreatedFile(testDir string, prefix string, t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, matchedPaths, 1, "%+v", matchedPaths)
 }
+
+// Replace stdin with the input for interactive prompts
+func setStdin(t *testing.T, input string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "stdin")
+	require.NoError(t, os.WriteFile(path, []byte(input), 0o600))
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+
+	original := os.Stdin
+	os.Stdin = file
+
+	t.Cleanup(func() {
+		os.Stdin = original
+		file.Close()
+	})
+}
+
+// Create a sync directory with one subDir that is isolated from the notes of other tests
+func resetTmpSyncDir(t *testing.T, name string) (string, string) {
+	syncDir := resetTmpTestDir(t, name)
+	subDir := filepath.Join(syncDir, "notes")
+
+	err := os.Mkdir(subDir, os.ModePerm)
+	require.NoError(t, err)
+
+	return syncDir, subDir
+}
+
+// Replace stdout with a file while running fn and return what was written
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "stdout")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+
+	original := os.Stdout
+	os.Stdout = file
+
+	defer func() {
+		os.Stdout = original
+		file.Close()
+	}()
+
+	fn()
+
+	output, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	return string(output)
+}
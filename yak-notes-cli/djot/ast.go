@@ -0,0 +1,269 @@
+// Package djot parses djot markup to an AST with source positions and renders the AST to HTML or back to djot
+//
+// The syntax follows the reference at https://djot.net and the HTML output follows the reference implementation
+package djot
+
+import (
+	"sort"
+	"strings"
+)
+
+// Kind is the type of a node, which matches the tag used by the reference implementation
+type Kind string
+
+// Block nodes
+const (
+	KindDocument           Kind = "doc"
+	KindSection            Kind = "section"
+	KindParagraph          Kind = "para"
+	KindHeading            Kind = "heading"
+	KindThematicBreak      Kind = "thematic_break"
+	KindBlockQuote         Kind = "blockquote"
+	KindBulletList         Kind = "bullet_list"
+	KindOrderedList        Kind = "ordered_list"
+	KindTaskList           Kind = "task_list"
+	KindDefinitionList     Kind = "definition_list"
+	KindListItem           Kind = "list_item"
+	KindTaskListItem       Kind = "task_list_item"
+	KindDefinitionListItem Kind = "definition_list_item"
+	KindTerm               Kind = "term"
+	KindDefinition         Kind = "definition"
+	KindCodeBlock          Kind = "code_block"
+	KindRawBlock           Kind = "raw_block"
+	KindDiv                Kind = "div"
+	KindTable              Kind = "table"
+	KindCaption            Kind = "caption"
+	KindRow                Kind = "row"
+	KindCell               Kind = "cell"
+	KindReference          Kind = "reference"
+	KindFootnote           Kind = "footnote"
+)
+
+// Inline nodes
+const (
+	KindStr               Kind = "str"
+	KindSoftBreak         Kind = "softbreak"
+	KindHardBreak         Kind = "hardbreak"
+	KindNonBreakingSpace  Kind = "non_breaking_space"
+	KindEmph              Kind = "emph"
+	KindStrong            Kind = "strong"
+	KindMark              Kind = "mark"
+	KindInsert            Kind = "insert"
+	KindDelete            Kind = "delete"
+	KindSuperscript       Kind = "superscript"
+	KindSubscript         Kind = "subscript"
+	KindVerbatim          Kind = "verbatim"
+	KindInlineMath        Kind = "inline_math"
+	KindDisplayMath       Kind = "display_math"
+	KindRawInline         Kind = "raw_inline"
+	KindLink              Kind = "link"
+	KindImage             Kind = "image"
+	KindSpan              Kind = "span"
+	KindURL               Kind = "url"
+	KindEmail             Kind = "email"
+	KindFootnoteReference Kind = "footnote_reference"
+	KindSymbol            Kind = "symb"
+	KindDoubleQuoted      Kind = "double_quoted"
+	KindSingleQuoted      Kind = "single_quoted"
+	KindLeftDoubleQuote   Kind = "left_double_quote"
+	KindRightDoubleQuote  Kind = "right_double_quote"
+	KindLeftSingleQuote   Kind = "left_single_quote"
+	KindRightSingleQuote  Kind = "right_single_quote"
+	KindEllipses          Kind = "ellipses"
+	KindEnDash            Kind = "en_dash"
+	KindEmDash            Kind = "em_dash"
+)
+
+// Align is the alignment of a table cell
+type Align string
+
+const (
+	AlignDefault Align = ""
+	AlignLeft    Align = "left"
+	AlignCenter  Align = "center"
+	AlignRight   Align = "right"
+)
+
+// Pos is a location in the source. Line and Col start at 1 and Col counts bytes
+type Pos struct {
+	Line   int
+	Col    int
+	Offset int
+}
+
+// Node is an element of the AST. Fields that don't apply to the Kind are left empty
+type Node struct {
+	Kind     Kind
+	Children []*Node
+	Attrs    Attributes
+	// Start is the first byte of the node in the source and End is the position after the last byte
+	Start Pos
+	End   Pos
+	// Text is the content of text, verbatim, math, raw, code block, and symbol nodes
+	Text string
+	// Level of headings and sections
+	Level int
+	// Dest is the destination of links, images, and reference definitions
+	Dest string
+	// Label of reference links, reference definitions, footnotes, and footnote references
+	Label string
+	// Format of raw nodes and the language of code blocks
+	Format string
+	// Style is the list marker without the number, such as "-", "1.", "a)", "(i)", or ":"
+	Style string
+	// StartNumber of ordered lists
+	StartNumber int
+	// Tight lists don't wrap the content of items in paragraphs
+	Tight bool
+	// Checked task list items
+	Checked bool
+	// Align of table cells
+	Align Align
+	// Head is set on header rows and cells of tables
+	Head bool
+}
+
+// Walk visits the node and the descendants depth-first until fn returns false
+func Walk(node *Node, fn func(node *Node) bool) bool {
+	if !fn(node) {
+		return false
+	}
+
+	for _, child := range node.Children {
+		if !Walk(child, fn) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// TextContent is the plain text of the node with markup removed
+func (n *Node) TextContent() string {
+	return n.textContent(smartText)
+}
+
+// The plain text with smart punctuation written as the text in smart
+func (n *Node) textContent(smart map[Kind]string) string {
+	children := func() string {
+		var b strings.Builder
+		for _, child := range n.Children {
+			b.WriteString(child.textContent(smart))
+		}
+
+		return b.String()
+	}
+
+	switch n.Kind {
+	case KindStr, KindVerbatim, KindInlineMath, KindDisplayMath, KindURL, KindEmail, KindCodeBlock:
+		return n.Text
+	case KindSoftBreak, KindHardBreak, KindNonBreakingSpace:
+		return " "
+	case KindSymbol:
+		return ":" + n.Text + ":"
+	case KindRawInline, KindRawBlock, KindFootnoteReference:
+		return ""
+	case KindDoubleQuoted:
+		return smart[KindLeftDoubleQuote] + children() + smart[KindRightDoubleQuote]
+	case KindSingleQuoted:
+		return smart[KindLeftSingleQuote] + children() + smart[KindRightSingleQuote]
+	default:
+		return smart[n.Kind] + children()
+	}
+}
+
+// The rendered text of smart punctuation
+var smartText = map[Kind]string{
+	KindLeftDoubleQuote:  "“",
+	KindRightDoubleQuote: "”",
+	KindLeftSingleQuote:  "‘",
+	KindRightSingleQuote: "’",
+	KindEllipses:         "…",
+	KindEnDash:           "–",
+	KindEmDash:           "—",
+}
+
+// Attribute is a single key and value from `{#id .class key="value"}`
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// Attributes are kept in the order they were written
+type Attributes []Attribute
+
+func (a Attributes) Get(key string) (string, bool) {
+	for _, attr := range a {
+		if attr.Key == key {
+			return attr.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// Set replaces the value or appends the key when new
+func (a *Attributes) Set(key, value string) {
+	for i, attr := range *a {
+		if attr.Key == key {
+			(*a)[i].Value = value
+			return
+		}
+	}
+
+	*a = append(*a, Attribute{Key: key, Value: value})
+}
+
+func (a *Attributes) Delete(key string) {
+	for i, attr := range *a {
+		if attr.Key == key {
+			*a = append((*a)[:i], (*a)[i+1:]...)
+			return
+		}
+	}
+}
+
+// AddClass appends to the space separated classes
+func (a *Attributes) AddClass(class string) {
+	if classes, ok := a.Get("class"); ok && classes != "" {
+		a.Set("class", classes+" "+class)
+	} else {
+		a.Set("class", class)
+	}
+}
+
+// Merge sets each attribute of other, where classes are combined
+func (a *Attributes) Merge(other Attributes) {
+	for _, attr := range other {
+		if attr.Key == "class" {
+			a.AddClass(attr.Value)
+		} else {
+			a.Set(attr.Key, attr.Value)
+		}
+	}
+}
+
+// Document is the root of the AST with the definitions that are referenced from the content
+type Document struct {
+	*Node
+	// References maps the normalized labels of reference definitions
+	References map[string]*Node
+	// Footnotes maps the labels of footnote definitions
+	Footnotes  map[string]*Node
+	lineStarts []int
+}
+
+// Position converts a byte offset in the source to a Pos
+func (d *Document) Position(offset int) Pos {
+	line := sort.Search(len(d.lineStarts), func(i int) bool { return d.lineStarts[i] > offset }) - 1
+	if line < 0 {
+		line = 0
+	}
+
+	return Pos{Line: line + 1, Col: offset - d.lineStarts[line] + 1, Offset: offset}
+}
+
+// Normalize a reference label, where whitespace differences are ignored
+func normalizeLabel(label string) string {
+	return strings.Join(strings.Fields(label), " ")
+}
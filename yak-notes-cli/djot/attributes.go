@@ -0,0 +1,176 @@
+package djot
+
+import "strings"
+
+type attrStatus int
+
+const (
+	attrsInvalid attrStatus = iota
+	// attrsIncomplete is returned when the input ended before the closing brace
+	attrsIncomplete
+	attrsDone
+)
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// Names of ids, classes, and keys. Non-ASCII bytes are allowed so that names can use any letter
+func isNameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '-' || c == '_' || c == ':' || c >= 0x80
+}
+
+func scanName(s string, i int) int {
+	for i < len(s) && isNameChar(s[i]) {
+		i++
+	}
+
+	return i
+}
+
+// Read a quoted value starting after the opening quote, returning the value and the index after the closing quote
+func scanQuoted(s string, i int) (string, int, bool) {
+	var b strings.Builder
+
+	for ; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s):
+			i++
+			b.WriteByte(s[i])
+		case c == '"':
+			return b.String(), i + 1, true
+		case c == '\n' || c == '\r':
+			b.WriteByte(' ')
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return "", i, false
+}
+
+// Parse `{#id .class key=value key="quoted value" %comment%}` starting at the opening brace
+//
+// The index after the closing brace is returned when done
+func parseAttributes(s string, start int) (Attributes, int, attrStatus) {
+	attrs := Attributes{}
+	i := start + 1
+
+	for {
+		for i < len(s) && isSpace(s[i]) {
+			i++
+		}
+
+		if i >= len(s) {
+			return nil, i, attrsIncomplete
+		}
+
+		switch c := s[i]; {
+		case c == '}':
+			return attrs, i + 1, attrsDone
+		case c == '%':
+			end := strings.IndexByte(s[i+1:], '%')
+			if end < 0 {
+				return nil, len(s), attrsIncomplete
+			}
+
+			i += end + 2
+		case c == '#' || c == '.':
+			end := scanName(s, i+1)
+			if end == i+1 {
+				return nil, i, attrsInvalid
+			}
+
+			if c == '#' {
+				attrs.Set("id", s[i+1:end])
+			} else {
+				attrs.AddClass(s[i+1 : end])
+			}
+
+			i = end
+		case isNameChar(c):
+			end := scanName(s, i)
+			if end >= len(s) {
+				return nil, end, attrsIncomplete
+			} else if s[end] != '=' {
+				return nil, end, attrsInvalid
+			}
+
+			key := s[i:end]
+			i = end + 1
+
+			switch {
+			case i >= len(s):
+				return nil, i, attrsIncomplete
+			case s[i] == '"':
+				value, next, ok := scanQuoted(s, i+1)
+				if !ok {
+					return nil, next, attrsIncomplete
+				}
+
+				attrs.setParsed(key, value)
+				i = next
+			default:
+				next := scanName(s, i)
+				if next == i {
+					return nil, i, attrsInvalid
+				}
+
+				attrs.setParsed(key, s[i:next])
+				i = next
+			}
+		default:
+			return nil, i, attrsInvalid
+		}
+
+		if i < len(s) && !isSpace(s[i]) && s[i] != '}' && s[i] != '%' {
+			return nil, i, attrsInvalid
+		}
+	}
+}
+
+// Classes given as a key are added to the classes from `.class`
+func (a *Attributes) setParsed(key, value string) {
+	if key == "class" {
+		a.AddClass(value)
+	} else {
+		a.Set(key, value)
+	}
+}
+
+func quoteValue(value string) string {
+	if value != "" && scanName(value, 0) == len(value) {
+		return value
+	}
+
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// String formats the attributes in djot syntax, such as `{#id .class key="value"}`
+func (a Attributes) String() string {
+	if len(a) == 0 {
+		return ""
+	}
+
+	parts := []string{}
+
+	for _, attr := range a {
+		switch {
+		case attr.Key == "id" && attr.Value != "" && scanName(attr.Value, 0) == len(attr.Value):
+			parts = append(parts, "#"+attr.Value)
+		case attr.Key == "class" && attr.Value != "":
+			for class := range strings.FieldsSeq(attr.Value) {
+				if scanName(class, 0) == len(class) {
+					parts = append(parts, "."+class)
+				} else {
+					parts = append(parts, "class="+quoteValue(class))
+				}
+			}
+		default:
+			parts = append(parts, attr.Key+"="+quoteValue(attr.Value))
+		}
+	}
+
+	return "{" + strings.Join(parts, " ") + "}"
+}
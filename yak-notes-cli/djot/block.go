@@ -0,0 +1,948 @@
+package djot
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	headingMarkerRe = regexp.MustCompile(`^(#{1,6})(?:[ \t]+|$)`)
+	thematicBreakRe = regexp.MustCompile(`^(?:[-*][ \t]*){3,}$`)
+	codeFenceRe     = regexp.MustCompile("^(`{3,}|~{3,})[ \t]*([^`\\s]*)[ \t]*$")
+	divFenceRe      = regexp.MustCompile(`^(:{3,})[ \t]*(\S*)[ \t]*$`)
+	footnoteDefRe   = regexp.MustCompile(`^\[\^([^\]]+)\]:(?:[ \t]+|$)`)
+	referenceDefRe  = regexp.MustCompile(`^\[([^\]^][^\]]*)\]:(?:[ \t]+(\S*))?[ \t]*$`)
+	orderedMarkerRe = regexp.MustCompile(`^(\()?([0-9]+|[a-zA-Z]+)([.)])(?:[ \t]|$)`)
+	separatorCellRe = regexp.MustCompile(`^[ \t]*(:?)-+(:?)[ \t]*$`)
+	romanLowerRe    = regexp.MustCompile(`^[ivxlcdm]+$`)
+	romanUpperRe    = regexp.MustCompile(`^[IVXLCDM]+$`)
+)
+
+// A span of inline text and the offset of the first byte in the source
+type segment struct {
+	offset int
+	text   string
+}
+
+// block is an open block while the lines are parsed
+type block struct {
+	node *Node
+	// end is the offset after the last non-blank line of the block
+	end int
+	// fence of code blocks and divs
+	fence string
+	// indent is the column of the list marker, footnote label, or code fence
+	indent int
+	// segments of text that are parsed as inlines when the block is closed
+	segments []segment
+	code     strings.Builder
+	// styles that remain possible for an ordered list, such as "a." and "i." for `i.`
+	styles        []string
+	enumerator    string
+	lastLineBlank bool
+	loose         bool
+	// sawBlank is set on tables after a blank line, where only a caption can follow
+	sawBlank bool
+	aligns   []Align
+}
+
+type parser struct {
+	doc   *Document
+	stack []*block
+	// pending are the block attributes for the next block
+	pending Attributes
+	// The current line without the line ending, the offset of the line, and the position in the line
+	line       string
+	lineOffset int
+	pos        int
+	// Scans of the line that are shared by nested containers, which would otherwise repeat them at every level: the
+	// end of the text before trailing whitespace, the start of the trailing run of thematic break characters, and the
+	// last position that indent was computed from and its result
+	textEnd    int
+	breakStart int
+	indentFrom int
+	indentTo   int
+}
+
+// Parse reads djot markup into an AST. Every input is valid djot, so there are no errors
+func Parse(src string) *Document {
+	root := &Node{Kind: KindDocument}
+	p := &parser{
+		doc:   &Document{Node: root, References: map[string]*Node{}, Footnotes: map[string]*Node{}},
+		stack: []*block{{node: root}},
+	}
+
+	for offset := 0; offset < len(src) || offset == 0; {
+		end, next := len(src), len(src)
+		if idx := strings.IndexByte(src[offset:], '\n'); idx >= 0 {
+			end, next = offset+idx, offset+idx+1
+		}
+
+		p.doc.lineStarts = append(p.doc.lineStarts, offset)
+		p.processLine(strings.TrimSuffix(src[offset:end], "\r"), offset)
+
+		if next == offset {
+			break
+		}
+
+		offset = next
+	}
+
+	for len(p.stack) > 1 {
+		p.closeTip()
+	}
+
+	root.End = Pos{Offset: len(src)}
+	p.finish()
+
+	return p.doc
+}
+
+func (p *parser) tip() *block {
+	return p.stack[len(p.stack)-1]
+}
+
+func (p *parser) rest() string {
+	return p.line[p.pos:]
+}
+
+func (p *parser) restBlank() bool {
+	return p.pos >= p.textEnd
+}
+
+func (p *parser) skipSpaces() {
+	for p.pos < len(p.line) && (p.line[p.pos] == ' ' || p.line[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// Column of the first non-space character from the current position
+func (p *parser) indent() int {
+	if p.pos == p.indentFrom {
+		return p.indentTo
+	}
+
+	i := p.pos
+	for i < len(p.line) && (p.line[i] == ' ' || p.line[i] == '\t') {
+		i++
+	}
+
+	p.indentFrom, p.indentTo = p.pos, i
+
+	return i
+}
+
+// A thematic break from the current position, which is after spaces
+func (p *parser) restThematicBreak() bool {
+	if p.pos < p.breakStart {
+		return false
+	}
+
+	count := 0
+	for i := p.pos; i < len(p.line) && count < 3; i++ {
+		if p.line[i] == '-' || p.line[i] == '*' {
+			count++
+		}
+	}
+
+	return count == 3
+}
+
+func isLeaf(kind Kind) bool {
+	switch kind {
+	case KindParagraph, KindHeading, KindCodeBlock, KindRawBlock, KindCaption, KindReference, KindThematicBreak:
+		return true
+	default:
+		return false
+	}
+}
+
+func isList(kind Kind) bool {
+	return kind == KindBulletList || kind == KindOrderedList || kind == KindTaskList || kind == KindDefinitionList
+}
+
+func isListItem(kind Kind) bool {
+	return kind == KindListItem || kind == KindTaskListItem || kind == KindDefinitionListItem
+}
+
+func canContain(parent, child Kind) bool {
+	switch {
+	case isLeaf(parent):
+		return false
+	case isList(parent):
+		return isListItem(child)
+	case parent == KindTable:
+		return child == KindCaption
+	default:
+		return !isListItem(child) && child != KindCaption
+	}
+}
+
+type continuation int
+
+const (
+	continueFailed continuation = iota
+	continueMatched
+	// continueDone is returned when the line was consumed, such as by a closing fence
+	continueDone
+)
+
+// Start parsing a line from the beginning
+func (p *parser) setLine(line string, offset int) {
+	p.line, p.lineOffset, p.pos = line, offset, 0
+	p.textEnd = len(strings.TrimRightFunc(line, unicode.IsSpace))
+	p.breakStart = len(strings.TrimRight(line, "-* \t"))
+	p.indentFrom = -1
+}
+
+func (p *parser) processLine(line string, offset int) {
+	p.setLine(line, offset)
+
+	matched := 0
+
+	for i := 1; i < len(p.stack); i++ {
+		result := p.continueBlock(i)
+		if result == continueFailed {
+			break
+		}
+
+		matched = i
+
+		if result == continueDone {
+			p.markBlank(false)
+			return
+		}
+	}
+
+	tip := p.tip()
+	if matched == len(p.stack)-1 && isLeaf(tip.node.Kind) {
+		p.addLine(tip)
+		p.markBlank(false)
+
+		return
+	}
+
+	started := false
+
+	for {
+		p.skipSpaces()
+
+		start := p.detectStart()
+		if start == nil {
+			break
+		}
+
+		if !started {
+			p.closeFrom(matched + 1)
+			started = true
+		}
+
+		if !start() {
+			p.markBlank(false)
+			return
+		}
+	}
+
+	blank := p.restBlank()
+
+	switch {
+	case !started && !blank && matched < len(p.stack)-1 && tip.node.Kind == KindParagraph:
+		// Lazy continuation of a paragraph
+		p.addLine(tip)
+	case !blank:
+		if !started {
+			p.closeFrom(matched + 1)
+		}
+
+		p.addLine(p.open(KindParagraph))
+	case !started:
+		p.closeFrom(matched + 1)
+	}
+
+	p.markBlank(blank)
+}
+
+// Record whether the line was blank and extend the open blocks to the end of a non-blank line
+func (p *parser) markBlank(blank bool) {
+	for _, b := range p.stack {
+		b.lastLineBlank = blank
+
+		if !blank {
+			b.end = p.lineOffset + len(p.line)
+		}
+	}
+}
+
+func (p *parser) continueBlock(i int) continuation {
+	b := p.stack[i]
+
+	switch b.node.Kind {
+	case KindBlockQuote:
+		p.skipSpaces()
+
+		if p.pos < len(p.line) && p.line[p.pos] == '>' {
+			if p.pos+1 == len(p.line) {
+				p.pos++
+				return continueMatched
+			} else if c := p.line[p.pos+1]; c == ' ' || c == '\t' {
+				p.pos += 2
+				return continueMatched
+			}
+		}
+
+		return continueFailed
+	case KindListItem, KindTaskListItem, KindDefinitionListItem, KindFootnote:
+		if p.restBlank() || p.indent() > b.indent {
+			return continueMatched
+		}
+
+		return continueFailed
+	case KindCodeBlock, KindRawBlock:
+		if p.isClosingFence(b) {
+			p.stack[i].end = p.lineOffset + len(p.line)
+			p.closeFrom(i)
+
+			return continueDone
+		}
+
+		return continueMatched
+	case KindDiv:
+		if k := p.tip().node.Kind; k != KindCodeBlock && k != KindRawBlock && p.isClosingFence(b) {
+			p.stack[i].end = p.lineOffset + len(p.line)
+			p.closeFrom(i)
+
+			return continueDone
+		}
+
+		return continueMatched
+	case KindTable:
+		return p.continueTable(b)
+	case KindParagraph, KindCaption:
+		if p.restBlank() {
+			return continueFailed
+		}
+
+		return continueMatched
+	case KindHeading:
+		if p.restBlank() {
+			return continueFailed
+		}
+
+		p.skipSpaces()
+
+		if m := headingMarkerRe.FindString(p.rest()); m != "" && len(strings.TrimRight(m, " \t")) == b.node.Level {
+			p.pos += len(m)
+		}
+
+		return continueMatched
+	case KindReference:
+		if !p.restBlank() && p.indent() > b.indent {
+			return continueMatched
+		}
+
+		return continueFailed
+	default:
+		// Lists are closed when a block other than an item is added
+		return continueMatched
+	}
+}
+
+// A line with only the fence character that is at least as long as the opening fence
+func (p *parser) isClosingFence(b *block) bool {
+	rest := strings.TrimSpace(p.rest())
+	return len(rest) >= len(b.fence) && countRun(rest, 0, b.fence[0]) == len(rest)
+}
+
+// Add the rest of the line to a leaf block
+func (p *parser) addLine(b *block) {
+	switch b.node.Kind {
+	case KindCodeBlock, KindRawBlock:
+		for p.pos < b.indent && p.pos < len(p.line) && (p.line[p.pos] == ' ' || p.line[p.pos] == '\t') {
+			p.pos++
+		}
+
+		b.code.WriteString(p.rest() + "\n")
+	case KindReference:
+		b.node.Dest += strings.TrimSpace(p.rest())
+	default:
+		p.skipSpaces()
+		b.segments = append(b.segments, segment{offset: p.lineOffset + p.pos, text: p.rest()})
+	}
+
+	p.pos = len(p.line)
+}
+
+// Add a block as the last child of the innermost open block that can contain it
+func (p *parser) open(kind Kind) *block {
+	for !canContain(p.tip().node.Kind, kind) {
+		p.closeTip()
+	}
+
+	parent := p.tip()
+	b := &block{node: &Node{Kind: kind, Start: Pos{Offset: p.lineOffset + p.pos}}, end: p.lineOffset + len(p.line)}
+
+	if parent.lastLineBlank && len(parent.node.Children) > 0 {
+		switch {
+		case isList(parent.node.Kind):
+			parent.loose = true
+		case isListItem(parent.node.Kind) && !isList(kind):
+			p.stack[len(p.stack)-2].loose = true
+		}
+	}
+
+	if len(p.pending) > 0 && !isListItem(kind) {
+		b.node.Attrs.Merge(p.pending)
+		p.pending = nil
+	}
+
+	parent.node.Children = append(parent.node.Children, b.node)
+	p.stack = append(p.stack, b)
+
+	return b
+}
+
+func (p *parser) closeFrom(i int) {
+	for len(p.stack) > i {
+		p.closeTip()
+	}
+}
+
+func (p *parser) closeTip() {
+	b := p.tip()
+	p.stack = p.stack[:len(p.stack)-1]
+	node := b.node
+	node.End = Pos{Offset: b.end}
+
+	switch node.Kind {
+	case KindParagraph, KindHeading, KindCaption:
+		node.Children = parseInlines(b.segments)
+	case KindCodeBlock, KindRawBlock:
+		node.Text = b.code.String()
+	case KindReference:
+		if _, ok := p.doc.References[node.Label]; !ok {
+			p.doc.References[node.Label] = node
+		}
+	case KindFootnote:
+		if _, ok := p.doc.Footnotes[node.Label]; !ok {
+			p.doc.Footnotes[node.Label] = node
+		}
+	case KindBulletList, KindOrderedList, KindTaskList, KindDefinitionList:
+		node.Tight = !b.loose
+
+		if node.Kind == KindOrderedList {
+			node.Style = b.styles[0]
+			node.StartNumber = enumeratorValue(b.enumerator, node.Style)
+		}
+	case KindDefinitionListItem:
+		splitDefinition(node)
+	}
+}
+
+// The first paragraph of a definition list item is the term and the other blocks are the definition
+func splitDefinition(item *Node) {
+	term := &Node{Kind: KindTerm, Start: item.Start, End: item.Start}
+	definition := &Node{Kind: KindDefinition, Start: item.End, End: item.End}
+	blocks := item.Children
+
+	if len(blocks) > 0 && blocks[0].Kind == KindParagraph {
+		term.Children, term.Start, term.End = blocks[0].Children, blocks[0].Start, blocks[0].End
+		blocks = blocks[1:]
+	}
+
+	if len(blocks) > 0 {
+		definition.Children, definition.Start = blocks, blocks[0].Start
+	}
+
+	item.Children = []*Node{term, definition}
+}
+
+// Find the block that starts at the current position and return the function that opens it
+//
+// The function returns true when the block is a container, where the rest of the line can start more blocks
+func (p *parser) detectStart() func() bool {
+	rest := p.rest()
+	if rest == "" {
+		return nil
+	}
+
+	switch c := rest[0]; c {
+	case '>':
+		if len(rest) == 1 || rest[1] == ' ' || rest[1] == '\t' {
+			return p.startBlockQuote
+		}
+	case '#':
+		if m := headingMarkerRe.FindString(rest); m != "" {
+			return func() bool { return p.startHeading(m) }
+		}
+	case '`', '~':
+		if m := codeFenceRe.FindStringSubmatch(rest); m != nil {
+			return func() bool { return p.startCodeBlock(m[1], m[2]) }
+		}
+	case ':':
+		if m := divFenceRe.FindStringSubmatch(rest); m != nil {
+			return func() bool { return p.startDiv(m[1], m[2]) }
+		}
+	case '[':
+		if m := footnoteDefRe.FindStringSubmatch(rest); m != nil {
+			return func() bool { return p.startFootnote(m[0], m[1]) }
+		} else if m := referenceDefRe.FindStringSubmatch(rest); m != nil {
+			return func() bool { return p.startReference(m[1], m[2]) }
+		}
+	case '|':
+		if _, ok := splitRow(rest); ok {
+			return p.startTable
+		}
+	case '{':
+		if attrs, end, status := parseAttributes(rest, 0); status == attrsDone && strings.TrimSpace(rest[end:]) == "" {
+			return func() bool { return p.addBlockAttributes(attrs) }
+		}
+	case '^':
+		if p.tip().node.Kind == KindTable && (len(rest) == 1 || rest[1] == ' ') {
+			return p.startCaption
+		}
+	}
+
+	if p.restThematicBreak() {
+		return p.startThematicBreak
+	}
+
+	if marker, ok := parseListMarker(rest); ok {
+		return func() bool { return p.startListItem(marker) }
+	}
+
+	return nil
+}
+
+func (p *parser) startBlockQuote() bool {
+	p.open(KindBlockQuote)
+	p.pos++
+
+	if p.pos < len(p.line) {
+		p.pos++
+	}
+
+	return true
+}
+
+func (p *parser) startHeading(marker string) bool {
+	b := p.open(KindHeading)
+	b.node.Level = len(strings.TrimRight(marker, " \t"))
+	p.pos += len(marker)
+	p.addLine(b)
+
+	return false
+}
+
+func (p *parser) startCodeBlock(fence, lang string) bool {
+	b := p.open(KindCodeBlock)
+	b.fence, b.indent = fence, p.pos
+
+	if format, ok := strings.CutPrefix(lang, "="); ok {
+		b.node.Kind, b.node.Format = KindRawBlock, format
+	} else {
+		b.node.Format = lang
+	}
+
+	p.pos = len(p.line)
+
+	return false
+}
+
+func (p *parser) startDiv(fence, class string) bool {
+	b := p.open(KindDiv)
+	b.fence = fence
+
+	if class != "" {
+		b.node.Attrs.AddClass(class)
+	}
+
+	p.pos = len(p.line)
+
+	return false
+}
+
+func (p *parser) startFootnote(marker, label string) bool {
+	b := p.open(KindFootnote)
+	b.node.Label, b.indent = label, p.pos
+	p.pos += len(marker)
+
+	return true
+}
+
+func (p *parser) startReference(label, dest string) bool {
+	b := p.open(KindReference)
+	b.node.Label, b.node.Dest, b.indent = normalizeLabel(label), dest, p.pos
+	p.pos = len(p.line)
+
+	return false
+}
+
+func (p *parser) startThematicBreak() bool {
+	p.open(KindThematicBreak)
+	p.pos = len(p.line)
+	p.tip().end = p.lineOffset + len(p.line)
+	p.closeTip()
+
+	return false
+}
+
+func (p *parser) startCaption() bool {
+	// The start is detected before the table is closed by a line it doesn't continue, where the caret is text
+	if p.tip().node.Kind != KindTable {
+		p.addLine(p.open(KindParagraph))
+		return false
+	}
+
+	b := p.open(KindCaption)
+	p.pos++
+	p.addLine(b)
+
+	return false
+}
+
+func (p *parser) addBlockAttributes(attrs Attributes) bool {
+	p.pending.Merge(attrs)
+	p.pos = len(p.line)
+
+	return false
+}
+
+type listMarker struct {
+	kind    Kind
+	styles  []string
+	value   string
+	width   int
+	checked bool
+}
+
+// Parse a bullet, task, definition, or ordered list marker that is followed by whitespace
+func parseListMarker(rest string) (listMarker, bool) {
+	followed := func(i int) bool { return i == len(rest) || rest[i] == ' ' || rest[i] == '\t' }
+
+	switch c := rest[0]; {
+	case c == '-' || c == '+' || c == '*':
+		if !followed(1) {
+			return listMarker{}, false
+		}
+
+		if len(rest) >= 5 && rest[1] == ' ' && rest[2] == '[' && rest[4] == ']' && followed(5) &&
+			(rest[3] == ' ' || rest[3] == 'x' || rest[3] == 'X') {
+			return listMarker{kind: KindTaskList, styles: []string{rest[:1]}, width: 5, checked: rest[3] != ' '}, true
+		}
+
+		return listMarker{kind: KindBulletList, styles: []string{rest[:1]}, width: 1}, true
+	case c == ':':
+		if followed(1) {
+			return listMarker{kind: KindDefinitionList, styles: []string{":"}, width: 1}, true
+		}
+
+		return listMarker{}, false
+	}
+
+	m := orderedMarkerRe.FindStringSubmatch(rest)
+	if m == nil || (m[1] == "(" && m[3] != ")") {
+		return listMarker{}, false
+	}
+
+	styles := enumeratorStyles(m[2])
+	if len(styles) == 0 {
+		return listMarker{}, false
+	}
+
+	for i, style := range styles {
+		if m[1] == "(" {
+			styles[i] = "(" + style + ")"
+		} else {
+			styles[i] = style + m[3]
+		}
+	}
+
+	return listMarker{kind: KindOrderedList, styles: styles, value: m[2], width: len(m[1] + m[2] + m[3])}, true
+}
+
+// The possible styles of an enumerator, where a single letter like `i` can be a letter or a roman numeral
+func enumeratorStyles(enumerator string) []string {
+	switch {
+	case enumerator[0] >= '0' && enumerator[0] <= '9':
+		return []string{"1"}
+	case len(enumerator) == 1 && romanLowerRe.MatchString(enumerator):
+		if enumerator == "i" {
+			return []string{"i", "a"}
+		}
+
+		return []string{"a", "i"}
+	case len(enumerator) == 1 && romanUpperRe.MatchString(enumerator):
+		if enumerator == "I" {
+			return []string{"I", "A"}
+		}
+
+		return []string{"A", "I"}
+	case len(enumerator) == 1 && enumerator[0] >= 'a':
+		return []string{"a"}
+	case len(enumerator) == 1:
+		return []string{"A"}
+	case romanLowerRe.MatchString(enumerator):
+		return []string{"i"}
+	case romanUpperRe.MatchString(enumerator):
+		return []string{"I"}
+	default:
+		return nil
+	}
+}
+
+// The character that identifies the numbering of a style, such as `a` for `(a)`
+func styleNumbering(style string) byte {
+	return strings.Trim(style, "().")[0]
+}
+
+var romanValues = map[byte]int{'i': 1, 'v': 5, 'x': 10, 'l': 50, 'c': 100, 'd': 500, 'm': 1000}
+
+func enumeratorValue(enumerator, style string) int {
+	switch styleNumbering(style) {
+	case '1':
+		n, _ := strconv.Atoi(enumerator)
+		return n
+	case 'a':
+		return int(enumerator[0]-'a') + 1
+	case 'A':
+		return int(enumerator[0]-'A') + 1
+	default:
+		total := 0
+		lower := strings.ToLower(enumerator)
+
+		for i := range len(lower) {
+			value := romanValues[lower[i]]
+			if i+1 < len(lower) && romanValues[lower[i+1]] > value {
+				total -= value
+			} else {
+				total += value
+			}
+		}
+
+		return total
+	}
+}
+
+func intersectStyles(a, b []string) []string {
+	styles := []string{}
+
+	for _, style := range a {
+		for _, other := range b {
+			if style == other {
+				styles = append(styles, style)
+			}
+		}
+	}
+
+	return styles
+}
+
+var itemKinds = map[Kind]Kind{
+	KindBulletList:     KindListItem,
+	KindOrderedList:    KindListItem,
+	KindTaskList:       KindTaskListItem,
+	KindDefinitionList: KindDefinitionListItem,
+}
+
+func (p *parser) startListItem(marker listMarker) bool {
+	for isLeaf(p.tip().node.Kind) {
+		p.closeTip()
+	}
+
+	list := p.tip()
+	if isList(list.node.Kind) {
+		styles := intersectStyles(list.styles, marker.styles)
+		if list.node.Kind != marker.kind || len(styles) == 0 {
+			p.closeTip()
+
+			list = nil
+		} else {
+			list.styles = styles
+		}
+	} else {
+		list = nil
+	}
+
+	if list == nil {
+		list = p.open(marker.kind)
+		list.styles, list.enumerator = marker.styles, marker.value
+		list.node.Style = marker.styles[0]
+	}
+
+	item := p.open(itemKinds[marker.kind])
+	item.indent = p.pos
+	item.node.Checked = marker.checked
+	p.pos += marker.width
+
+	return true
+}
+
+func (p *parser) startTable() bool {
+	b := p.open(KindTable)
+	p.addRow(b)
+
+	return false
+}
+
+func (p *parser) continueTable(b *block) continuation {
+	if p.restBlank() {
+		b.sawBlank = true
+		return continueMatched
+	}
+
+	children := b.node.Children
+	hasCaption := len(children) > 0 && children[len(children)-1].Kind == KindCaption
+
+	p.skipSpaces()
+
+	switch {
+	case p.tip().node.Kind == KindCaption:
+		return continueMatched
+	case hasCaption:
+		return continueFailed
+	case strings.HasPrefix(p.rest(), "^ ") || p.rest() == "^":
+		return continueMatched
+	case b.sawBlank:
+		return continueFailed
+	}
+
+	if _, ok := splitRow(p.rest()); !ok {
+		return continueFailed
+	}
+
+	p.addRow(b)
+
+	return continueDone
+}
+
+// Split a table row into the byte ranges of the cells, or return false when the line isn't a row
+func splitRow(line string) ([][2]int, bool) {
+	line = strings.TrimRight(line, " \t")
+	if len(line) < 2 || line[0] != '|' || line[len(line)-1] != '|' {
+		return nil, false
+	}
+
+	cells := [][2]int{}
+	start := 1
+
+	for i := 1; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '`':
+			n := countRun(line, i, '`')
+			if end := findRun(line, i+n, '`', n); end >= 0 {
+				i = end + n - 1
+			} else {
+				i += n - 1
+			}
+		case '|':
+			cells = append(cells, [2]int{start, i})
+			start = i + 1
+		}
+	}
+
+	return cells, start == len(line) && len(cells) > 0
+}
+
+func (p *parser) addRow(b *block) {
+	rest := p.rest()
+	offset := p.lineOffset + p.pos
+	ranges, _ := splitRow(rest)
+
+	separator := true
+	aligns := make([]Align, len(ranges))
+
+	for i, r := range ranges {
+		m := separatorCellRe.FindStringSubmatch(rest[r[0]:r[1]])
+		if m == nil {
+			separator = false
+			break
+		}
+
+		switch {
+		case m[1] != "" && m[2] != "":
+			aligns[i] = AlignCenter
+		case m[1] != "":
+			aligns[i] = AlignLeft
+		case m[2] != "":
+			aligns[i] = AlignRight
+		}
+	}
+
+	rows := b.node.Children
+	if separator {
+		b.aligns = aligns
+
+		if len(rows) > 0 && rows[len(rows)-1].Kind == KindRow && !rows[len(rows)-1].Head {
+			last := rows[len(rows)-1]
+			last.Head = true
+
+			for i, cell := range last.Children {
+				cell.Head = true
+				if i < len(aligns) {
+					cell.Align = aligns[i]
+				}
+			}
+		}
+
+		p.pos = len(p.line)
+
+		return
+	}
+
+	row := &Node{Kind: KindRow, Start: Pos{Offset: offset}, End: Pos{Offset: offset + len(strings.TrimRight(rest, " \t"))}}
+
+	for i, r := range ranges {
+		text := rest[r[0]:r[1]]
+		trimmed := strings.TrimLeft(text, " \t")
+		start := offset + r[0] + len(text) - len(trimmed)
+		trimmed = strings.TrimRight(trimmed, " \t")
+
+		cell := &Node{
+			Kind:     KindCell,
+			Start:    Pos{Offset: offset + r[0] - 1},
+			End:      Pos{Offset: offset + r[1] + 1},
+			Children: parseInlines([]segment{{offset: start, text: trimmed}}),
+		}
+		if i < len(b.aligns) {
+			cell.Align = b.aligns[i]
+		}
+
+		row.Children = append(row.Children, cell)
+	}
+
+	b.node.Children = append(b.node.Children, row)
+	p.pos = len(p.line)
+}
+
+// The length of the run of char starting at i
+func countRun(s string, i int, char byte) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == char {
+		n++
+	}
+
+	return n
+}
+
+// The start of the next run of exactly n chars at or after i, or -1
+func findRun(s string, i int, char byte, n int) int {
+	for i < len(s) {
+		if s[i] != char {
+			i++
+			continue
+		}
+
+		run := countRun(s, i, char)
+		if run == n {
+			return i
+		}
+
+		i += run
+	}
+
+	return -1
+}
@@ -0,0 +1,860 @@
+package djot
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Inline delimiters that don't need braces when the content doesn't start or end with whitespace
+var inlineDelimiters = map[Kind]string{
+	KindEmph:        "_",
+	KindStrong:      "*",
+	KindSuperscript: "^",
+	KindSubscript:   "~",
+}
+
+// Inline delimiters that always need braces
+var bracedDelimiters = map[Kind]string{
+	KindMark:   "=",
+	KindInsert: "+",
+	KindDelete: "-",
+}
+
+// Literal markup of smart punctuation, where standalone double quotes are forced to the direction with braces
+var smartMarkup = map[Kind]string{
+	KindLeftDoubleQuote:  `{"`,
+	KindRightDoubleQuote: `"}`,
+	KindLeftSingleQuote:  "‘",
+	KindRightSingleQuote: "'",
+	KindEllipses:         "...",
+	KindEnDash:           "--",
+	KindEmDash:           "---",
+}
+
+type djotRenderer struct {
+	// inTable is set while rendering cells, where pipes are escaped
+	inTable bool
+	// bullet of the next list, which alternates so that adjacent lists stay separate, and is then set to the bullet that
+	// the list used
+	bullet string
+	// depth of the inlines being rendered, where 0 is outside of a block
+	depth int
+	// leftQuote is set after a standalone left double quote in the block, which a later quote could close
+	leftQuote bool
+	// open counts the enclosing nodes of each delimiter, which markup of the same delimiter could close, so nested
+	// openers are forced with braces and standalone quotes are written as typographic quotes
+	open map[string]int
+}
+
+// RenderDjot converts the AST back to djot in a canonical style
+//
+// Blocks are separated by one blank line, bullets use `-` or `+` where a line would otherwise be a thematic break, and
+// nested content is indented to the list marker
+func RenderDjot(doc *Document) string {
+	r := &djotRenderer{open: map[string]int{}}
+	return r.blocks(doc.Children)
+}
+
+func (r *djotRenderer) blocks(nodes []*Node) string {
+	return strings.Join(r.blockParts(nodes), "\n")
+}
+
+func (r *djotRenderer) blockParts(nodes []*Node) []string {
+	parts := []string{}
+	previous := "*"
+
+	for _, node := range nodes {
+		r.bullet = "-"
+		if previous == "-" {
+			r.bullet = "*"
+		}
+
+		if node.Kind == KindSection {
+			if rendered := r.blocks(node.Children); rendered != "" {
+				parts = append(parts, rendered)
+			}
+
+			previous = ""
+
+			continue
+		}
+
+		parts = append(parts, r.block(node))
+
+		previous = ""
+		if node.Kind == KindBulletList || node.Kind == KindTaskList {
+			previous = r.bullet
+		}
+	}
+
+	return parts
+}
+
+func blockAttrs(attrs Attributes) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+
+	return attrs.String() + "\n"
+}
+
+func (r *djotRenderer) block(node *Node) string {
+	switch node.Kind {
+	case KindParagraph:
+		return blockAttrs(node.Attrs) + escapeLineStarts(r.inlines(node.Children)) + "\n"
+	case KindHeading:
+		marker := strings.Repeat("#", node.Level)
+
+		// The text ends with a line break after a hard break, which is kept as a line of only the marker
+		return blockAttrs(node.Attrs) + prefixLines(r.inlines(node.Children)+"\n", marker+" ", marker+" ", marker)
+	case KindThematicBreak:
+		return blockAttrs(node.Attrs) + "* * *\n"
+	case KindBlockQuote:
+		return blockAttrs(node.Attrs) + prefixLines(r.blocks(node.Children), "> ", "> ", ">")
+	case KindBulletList, KindOrderedList, KindTaskList:
+		return blockAttrs(node.Attrs) + r.list(node)
+	case KindDefinitionList:
+		return blockAttrs(node.Attrs) + r.definitionList(node)
+	case KindCodeBlock, KindRawBlock:
+		fence := fenceFor(node.Text, '`', 3)
+		info := node.Format
+
+		if node.Kind == KindRawBlock {
+			info = "=" + node.Format
+		}
+
+		return blockAttrs(node.Attrs) + strings.TrimRight(fence+" "+info, " ") + "\n" + node.Text + fence + "\n"
+	case KindDiv:
+		return r.div(node)
+	case KindTable:
+		return blockAttrs(node.Attrs) + r.table(node)
+	case KindReference:
+		// Labels are normalized without surrounding whitespace, which is needed for a label that is empty or would be a
+		// footnote
+		label := node.Label
+		if label == "" || strings.HasPrefix(label, "^") {
+			label = " " + label
+		}
+
+		// A destination with whitespace can only be written on an indented continuation line
+		if strings.ContainsAny(node.Dest, " \t") {
+			return blockAttrs(node.Attrs) + "[" + label + "]:\n  " + node.Dest + "\n"
+		}
+
+		return blockAttrs(node.Attrs) + strings.TrimRight("["+label+"]: "+node.Dest, " ") + "\n"
+	case KindFootnote:
+		content := r.blocks(node.Children)
+		if content == "" {
+			return "[^" + node.Label + "]:\n"
+		}
+
+		return prefixLines(content, "[^"+node.Label+"]: ", "    ", "")
+	default:
+		return ""
+	}
+}
+
+// Prefix the first line and the other lines, where empty lines get the blank prefix
+func prefixLines(text, first, rest, blank string) string {
+	var b strings.Builder
+
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	for i, line := range lines {
+		switch {
+		case i == 0:
+			b.WriteString(strings.TrimRight(first+line, " "))
+		case line == "":
+			b.WriteString(blank)
+		default:
+			b.WriteString(rest + line)
+		}
+
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// A fence of char that is longer than any run of char in the content
+func fenceFor(content string, char byte, minimum int) string {
+	longest := 0
+
+	for i := 0; i < len(content); i++ {
+		if content[i] == char {
+			n := countRun(content, i, char)
+			longest = max(longest, n)
+			i += n - 1
+		}
+	}
+
+	return strings.Repeat(string(char), max(minimum, longest+1))
+}
+
+// Escape the start of lines in a paragraph that would otherwise be parsed as a block
+func escapeLineStarts(text string) string {
+	lines := strings.Split(text, "\n")
+
+	// Leading whitespace is removed from lines, so it is kept after empty attributes, which don't apply to anything, and
+	// a line of only whitespace has two sets that aren't block attributes and keep a following line break
+	for i, line := range lines {
+		switch {
+		case strings.TrimLeft(line, " \t") == "":
+			lines[i] = "{} {}" + line
+		case strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t"):
+			lines[i] = "{}" + line
+		}
+	}
+
+	p := &parser{stack: []*block{{node: &Node{Kind: KindDocument}}}}
+	p.setLine(lines[0], 0)
+
+	if p.detectStart() != nil {
+		lines[0] = escapeFirst(lines[0])
+	}
+
+	for i, line := range lines[1:] {
+		if len(line) >= 3 && countRun(line, 0, ':') == len(line) {
+			lines[i+1] = `\` + line
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func escapeFirst(line string) string {
+	// Text escapes these characters already, so they start inline markup that an escape would break, like the dashes
+	// of a line that looks like a thematic break
+	if strings.IndexByte(escapedChars, line[0]) >= 0 || thematicBreakRe.MatchString(line) {
+		return "{}" + line
+	}
+
+	if isASCIIPunct(line[0]) {
+		return `\` + line
+	}
+
+	if m := orderedMarkerRe.FindStringSubmatchIndex(line); m != nil {
+		return line[:m[6]] + `\` + line[m[6]:]
+	}
+
+	return line
+}
+
+func (r *djotRenderer) list(node *Node) string {
+	bullet := r.bullet
+	items := r.listItems(node, bullet)
+
+	// The line of a marker and content of dashes or asterisks would be a thematic break, unless the bullet is `+`
+	if slices.ContainsFunc(items, func(item string) bool {
+		line, _, _ := strings.Cut(item, "\n")
+		return thematicBreakRe.MatchString(line)
+	}) {
+		bullet = "+"
+		items = r.listItems(node, bullet)
+	}
+
+	r.bullet = bullet
+
+	if node.Tight {
+		return strings.Join(items, "")
+	}
+
+	return strings.Join(items, "\n")
+}
+
+func (r *djotRenderer) listItems(node *Node, bullet string) []string {
+	items := []string{}
+
+	for i, item := range node.Children {
+		marker := bullet
+
+		switch node.Kind {
+		case KindOrderedList:
+			marker = formatEnumerator(node.Style, node.StartNumber+i, len(node.Children) == 1)
+		case KindTaskList:
+			marker = bullet + " [ ]"
+			if item.Checked {
+				marker = bullet + " [x]"
+			}
+		}
+
+		// Blocks in the items of a tight list aren't separated by blank lines, other than before a nested list
+		content := ""
+		for j, part := range r.blockParts(item.Children) {
+			if j > 0 && (!node.Tight || isList(item.Children[j].Kind)) {
+				content += "\n"
+			}
+
+			content += part
+		}
+
+		if content == "" {
+			items = append(items, marker+"\n")
+			continue
+		}
+
+		items = append(items, prefixLines(content, marker+" ", strings.Repeat(" ", len(marker)+1), ""))
+	}
+
+	return items
+}
+
+// Format the number in the numbering and delimiter of the list style, where the enumerator of a list with a single
+// item can't rely on the other items to tell roman numerals from letters
+func formatEnumerator(style string, n int, single bool) string {
+	numbering := styleNumbering(style)
+
+	var formatted string
+
+	switch numbering {
+	case '1':
+		formatted = strconv.Itoa(n)
+	case 'a', 'A':
+		formatted = string(rune(int(numbering) + (n-1)%26))
+	case 'i':
+		formatted = strings.ToLower(roman(n))
+	default:
+		formatted = roman(n)
+	}
+
+	// A single letter other than i is a letter, so the numeral is written with an i that is subtracted and added
+	if i := string(numbering); single && len(formatted) == 1 && strings.EqualFold(i, "i") && formatted != i {
+		formatted = i + formatted + i
+	}
+
+	return strings.Replace(style, string(numbering), formatted, 1)
+}
+
+func roman(n int) string {
+	values := []int{1000, 900, 500, 400, 100, 90, 50, 40, 10, 9, 5, 4, 1}
+	symbols := []string{"M", "CM", "D", "CD", "C", "XC", "L", "XL", "X", "IX", "V", "IV", "I"}
+
+	var b strings.Builder
+
+	for i, value := range values {
+		for n >= value {
+			b.WriteString(symbols[i])
+			n -= value
+		}
+	}
+
+	return b.String()
+}
+
+func (r *djotRenderer) definitionList(node *Node) string {
+	items := []string{}
+
+	for _, item := range node.Children {
+		term, definition := item.Children[0], item.Children[1]
+		text := prefixLines(escapeLineStarts(r.inlines(term.Children)), ": ", "  ", "")
+
+		if content := r.blocks(definition.Children); content != "" {
+			text += "\n" + prefixLines(content, "  ", "  ", "")
+		}
+
+		items = append(items, text)
+	}
+
+	return strings.Join(items, "\n")
+}
+
+// Divs are nested with longer fences on the outside
+func divDepth(node *Node) int {
+	depth := 0
+
+	for _, child := range node.Children {
+		if child.Kind == KindDiv {
+			depth = max(depth, divDepth(child)+1)
+		} else if len(child.Children) > 0 && child.Kind != KindSection {
+			depth = max(depth, divDepth(child))
+		}
+	}
+
+	return depth
+}
+
+func (r *djotRenderer) div(node *Node) string {
+	fence := strings.Repeat(":", 3+divDepth(node))
+	attrs := append(Attributes{}, node.Attrs...)
+	opening := fence
+
+	if class, ok := attrs.Get("class"); ok && class != "" && scanName(class, 0) == len(class) {
+		opening += " " + class
+		attrs.Delete("class")
+	}
+
+	content := r.blocks(node.Children)
+
+	return blockAttrs(attrs) + opening + "\n" + content + fence + "\n"
+}
+
+// The alignments of a separator row for the head row before it, if any, and the rows after it, where the columns past
+// the end of the separator keep the previous alignments in the head and the default alignment in the rows
+func separatorAligns(head *Node, rows []*Node, previous []Align) ([]Align, bool) {
+	columns := 1
+	for _, row := range append([]*Node{head}, rows...) {
+		if row != nil {
+			columns = max(columns, len(row.Children))
+		}
+	}
+
+	fit := func(length int) ([]Align, bool) {
+		aligns := make([]Align, length)
+		set := make([]bool, length)
+
+		for _, row := range append([]*Node{head}, rows...) {
+			if row == nil {
+				continue
+			}
+
+			for i, cell := range row.Children {
+				expected := AlignDefault
+				if row == head && i < len(previous) {
+					expected = previous[i]
+				}
+
+				switch {
+				case i >= length && cell.Align != expected:
+					return nil, false
+				case i >= length:
+				case !set[i]:
+					aligns[i], set[i] = cell.Align, true
+				case aligns[i] != cell.Align:
+					return nil, false
+				}
+			}
+		}
+
+		return aligns, true
+	}
+
+	for length := 1; length <= columns; length++ {
+		if aligns, ok := fit(length); ok {
+			return aligns, true
+		}
+	}
+
+	return nil, false
+}
+
+func separatorRow(aligns []Align) string {
+	cells := []string{}
+	for _, align := range aligns {
+		cells = append(cells, map[Align]string{
+			AlignDefault: "---", AlignLeft: ":--", AlignCenter: ":-:", AlignRight: "--:",
+		}[align])
+	}
+
+	return "| " + strings.Join(cells, " | ") + " |\n"
+}
+
+func (r *djotRenderer) table(node *Node) string {
+	var b strings.Builder
+
+	r.inTable = true
+	defer func() { r.inTable = false }()
+
+	rows := []*Node{}
+	for _, child := range node.Children {
+		if child.Kind == KindRow {
+			rows = append(rows, child)
+		}
+	}
+
+	// The rows after a row up to the next head row, which share the alignments of the separator before them
+	body := func(i int) []*Node {
+		end := i + 1
+		for end < len(rows) && !rows[end].Head {
+			end++
+		}
+
+		return rows[i+1 : end]
+	}
+
+	aligns := []Align{}
+
+	for i, row := range rows {
+		if i == 0 && !row.Head {
+			aligns, _ = separatorAligns(nil, append([]*Node{row}, body(i)...), aligns)
+			if slices.ContainsFunc(aligns, func(align Align) bool { return align != AlignDefault }) {
+				b.WriteString(separatorRow(aligns))
+			}
+		}
+
+		cells := []string{}
+		dashes := len(row.Children) > 0
+
+		for _, cell := range row.Children {
+			cells = append(cells, r.inlines(cell.Children))
+			dashes = dashes && separatorCellRe.MatchString(cells[len(cells)-1])
+		}
+
+		// A row of dashes would be a separator row, where the dashes can be markup that an escape would break
+		if dashes {
+			cells[0] = "{}" + cells[0]
+		}
+
+		// An unclosed verbatim at the end of a cell ends at the cell, unless a later cell closes it
+		for j, cell := range row.Children {
+			if n := len(cell.Children); n > 0 && cell.Children[n-1].Kind == KindVerbatim && cell.Children[n-1].Text == "" {
+				fence := strings.Repeat("`", unusedRun(strings.Join(cells[j+1:], "|")))
+				cells[j] = strings.TrimSuffix(cells[j], "`") + fence
+			}
+		}
+
+		b.WriteString(strings.TrimRight("| "+strings.Join(cells, " | "), " ") + " |\n")
+
+		if !row.Head {
+			continue
+		}
+
+		// The rows can need other alignments than the head, which are set by a second separator row
+		separator, ok := separatorAligns(row, body(i), aligns)
+		if !ok {
+			separator, _ = separatorAligns(row, nil, aligns)
+			b.WriteString(separatorRow(separator))
+			separator, _ = separatorAligns(nil, body(i), separator)
+		}
+
+		aligns = separator
+		b.WriteString(separatorRow(aligns))
+	}
+
+	r.inTable = false
+
+	// A table of only separator rows has no cells, but is still a table
+	if b.Len() == 0 {
+		b.WriteString("| --- |\n")
+	}
+
+	for _, child := range node.Children {
+		if child.Kind == KindCaption {
+			b.WriteString(strings.TrimRight("^ "+r.inlines(child.Children), " ") + "\n")
+		}
+	}
+
+	return b.String()
+}
+
+func (r *djotRenderer) inlines(nodes []*Node) string {
+	if r.depth == 0 {
+		r.leftQuote = false
+	}
+
+	r.depth++
+	defer func() { r.depth-- }()
+
+	rendered := make([]string, len(nodes))
+
+	for i, node := range nodes {
+		switch {
+		case node.Kind == KindLeftDoubleQuote && len(node.Attrs) == 0 && r.betweenSpaces(nodes, i):
+			rendered[i] = `"`
+		case (node.Kind == KindLeftDoubleQuote || node.Kind == KindRightDoubleQuote) && r.open[`"`] > 0:
+			rendered[i] = smartText[node.Kind] + node.Attrs.String()
+		case node.Kind != KindStr:
+			rendered[i] = r.inline(node)
+		}
+	}
+
+	var b strings.Builder
+
+	for i, node := range nodes {
+		if node.Kind == KindStr {
+			prev, next := byte(0), ""
+			if b.Len() > 0 {
+				prev = b.String()[b.Len()-1]
+			}
+
+			if i+1 < len(nodes) {
+				next = rendered[i+1]
+			}
+
+			rendered[i] = r.escape(node.Text, prev, next)
+			if len(node.Attrs) > 0 {
+				rendered[i] = "[" + rendered[i] + "]" + node.Attrs.String()
+			}
+		}
+
+		// An apostrophe is only a closing quote after a letter or digit, and is otherwise forced to close
+		if node.Kind == KindRightSingleQuote {
+			switch {
+			case len(node.Attrs) == 0 && r.betweenSpaces(nodes, i):
+			case r.open["'"] > 0:
+				rendered[i] = smartText[node.Kind] + node.Attrs.String()
+			case b.Len() == 0 || !isAlnum(b.String()[b.Len()-1]):
+				rendered[i] = "'}" + node.Attrs.String()
+			}
+		}
+
+		// A single quote after a letter or digit is an apostrophe, unless it's forced to open
+		if node.Kind == KindSingleQuoted && b.Len() > 0 && isAlnum(b.String()[b.Len()-1]) &&
+			strings.HasPrefix(rendered[i], "'") {
+			rendered[i] = "{" + rendered[i]
+		}
+
+		// Trailing whitespace is removed from lines, so the escaped space is followed by empty attributes
+		if node.Kind == KindNonBreakingSpace && len(node.Attrs) == 0 &&
+			(i+1 == len(nodes) && r.depth == 1 || i+1 < len(nodes) && nodes[i+1].Kind == KindSoftBreak) {
+			rendered[i] += "{}"
+		}
+
+		b.WriteString(rendered[i])
+	}
+
+	return b.String()
+}
+
+// A left quote just before the closing quote has no content to match and doesn't close after the opening quote or a
+// space, so it's written as a quote that stays unmatched, unless an earlier or enclosing quote is open
+func (r *djotRenderer) trailingLeftQuote(node *Node) bool {
+	children := node.Children
+	n := len(children)
+
+	if node.Kind != KindDoubleQuoted || r.leftQuote || r.open[`"`] > 0 || n == 0 ||
+		children[n-1].Kind != KindLeftDoubleQuote || len(children[n-1].Attrs) > 0 {
+		return false
+	}
+
+	if n == 1 {
+		return true
+	}
+
+	prev := children[n-2]
+
+	return prev.Kind == KindStr && len(prev.Attrs) == 0 && strings.HasSuffix(prev.Text, " ")
+}
+
+// A quote between spaces, or at the start or end of a block, neither opens nor closes
+func (r *djotRenderer) betweenSpaces(nodes []*Node, i int) bool {
+	spaced := func(j int, prefix bool) bool {
+		if j < 0 || j == len(nodes) {
+			return r.depth == 1
+		}
+
+		node := nodes[j]
+		if node.Kind == KindSoftBreak {
+			return true
+		}
+
+		return node.Kind == KindStr && len(node.Attrs) == 0 &&
+			(prefix && strings.HasPrefix(node.Text, " ") || !prefix && strings.HasSuffix(node.Text, " "))
+	}
+
+	return spaced(i-1, false) && spaced(i+1, true)
+}
+
+// Characters that always start inline markup
+const escapedChars = "\\`*_[]{}^~<\"'"
+
+// Escape the characters of text that would otherwise be parsed as markup
+func (r *djotRenderer) escape(text string, prev byte, next string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		before, after := prev, byte(0)
+
+		if i > 0 {
+			before = text[i-1]
+		}
+
+		if i+1 < len(text) {
+			after = text[i+1]
+		} else if next != "" {
+			after = next[0]
+		}
+
+		switch {
+		case strings.IndexByte(escapedChars, c) >= 0,
+			c == '|' && r.inTable,
+			c == '-' && (before == '-' || after == '-'),
+			c == '.' && (before == '.' || after == '.'),
+			c == '$' && (after == '$' || after == '`'),
+			c == '!' && after == '[',
+			c == ':' && symbolRe.MatchString(text[i:]+next):
+			b.WriteByte('\\')
+		}
+
+		b.WriteByte(c)
+	}
+
+	return b.String()
+}
+
+// Wrap the content in the delimiter, with braces when the content starts or ends with whitespace
+func wrapDelimiter(delim, content string, braced bool) string {
+	// Delimiters only match around content, which can be empty attributes
+	if content == "" {
+		content = "{}"
+	}
+
+	if braced || isSpace(content[0]) || isSpace(content[len(content)-1]) {
+		return "{" + delim + content + delim + "}"
+	}
+
+	return delim + content + delim
+}
+
+// Backticks around the content that are longer than any run in the content
+func verbatimMarkup(content string) string {
+	// Only an unclosed verbatim at the end of a block is empty
+	if content == "" {
+		return "`"
+	}
+
+	// Verbatim closes at a run of the same length, so the shortest fence that isn't in the content is enough and
+	// rarely long enough to start a code block
+	length := unusedRun(content)
+
+	fence := strings.Repeat("`", length)
+	if strings.HasPrefix(content, "`") {
+		content = " " + content
+	}
+
+	if strings.HasSuffix(content, "`") {
+		content += " "
+	}
+
+	return fence + content + fence
+}
+
+// The shortest run of backticks that isn't in the text
+func unusedRun(text string) int {
+	runs := map[int]bool{}
+
+	for i := 0; i < len(text); i++ {
+		if text[i] == '`' {
+			n := countRun(text, i, '`')
+			runs[n] = true
+			i += n - 1
+		}
+	}
+
+	length := 1
+	for runs[length] {
+		length++
+	}
+
+	return length
+}
+
+// Escape parentheses in a destination unless they are balanced
+func escapeDestination(dest string) string {
+	depth := 0
+
+	for i := range len(dest) {
+		switch dest[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+
+		if depth < 0 {
+			break
+		}
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, "\n", "")
+	if depth != 0 {
+		replacer = strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`, "\n", "")
+	}
+
+	return replacer.Replace(dest)
+}
+
+func (r *djotRenderer) inline(node *Node) string {
+	text := r.inlineMarkup(node)
+
+	switch node.Kind {
+	case KindSpan, KindStr:
+		return text
+	default:
+		return text + node.Attrs.String()
+	}
+}
+
+func (r *djotRenderer) inlineMarkup(node *Node) string {
+	if delim, ok := inlineDelimiters[node.Kind]; ok {
+		r.open[delim]++
+		content := r.inlines(node.Children)
+		r.open[delim]--
+
+		// The opener of a nested node could close the enclosing node instead
+		return wrapDelimiter(delim, content, r.open[delim] > 0)
+	} else if delim, ok := bracedDelimiters[node.Kind]; ok {
+		return wrapDelimiter(delim, r.inlines(node.Children), true)
+	}
+
+	switch node.Kind {
+	case KindSoftBreak:
+		return "\n"
+	case KindHardBreak:
+		return "\\\n"
+	case KindNonBreakingSpace:
+		return `\ `
+	case KindVerbatim:
+		return verbatimMarkup(node.Text)
+	case KindInlineMath:
+		return "$" + verbatimMarkup(node.Text)
+	case KindDisplayMath:
+		return "$$" + verbatimMarkup(node.Text)
+	case KindRawInline:
+		return verbatimMarkup(node.Text) + "{=" + node.Format + "}"
+	case KindLink, KindImage:
+		prefix := "["
+		if node.Kind == KindImage {
+			prefix = "!["
+		}
+
+		content := r.inlines(node.Children)
+
+		switch {
+		// A label with a closing bracket can't be written, but also can't be defined, so the link is unresolved
+		// with any label
+		case node.Label != "" && (normalizeLabel(content) == node.Label || strings.Contains(node.Label, "]")):
+			return prefix + content + "][]"
+		case node.Label != "":
+			return prefix + content + "][" + node.Label + "]"
+		}
+
+		return prefix + content + "](" + escapeDestination(node.Dest) + ")"
+	case KindSpan:
+		if len(node.Attrs) == 0 {
+			return "[" + r.inlines(node.Children) + "]{}"
+		}
+
+		return "[" + r.inlines(node.Children) + "]" + node.Attrs.String()
+	case KindURL, KindEmail:
+		return "<" + node.Text + ">"
+	case KindFootnoteReference:
+		return "[^" + node.Label + "]"
+	case KindSymbol:
+		return ":" + node.Text + ":"
+	case KindDoubleQuoted, KindSingleQuoted:
+		delim := `"`
+		if node.Kind == KindSingleQuoted {
+			delim = "'"
+		}
+
+		children := node.Children
+		last := ""
+
+		if r.trailingLeftQuote(node) {
+			children, last = children[:len(children)-1], `"`
+		}
+
+		r.open[delim]++
+		content := r.inlines(children) + last
+		r.open[delim]--
+
+		// An opening quote could also close an earlier standalone quote, unless it's forced to open
+		return wrapDelimiter(delim, content, r.open[delim] > 0 || delim == `"` && r.leftQuote)
+	case KindLeftDoubleQuote:
+		r.leftQuote = true
+		return smartMarkup[node.Kind]
+	default:
+		return smartMarkup[node.Kind]
+	}
+}
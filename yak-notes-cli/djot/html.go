@@ -0,0 +1,405 @@
+package djot
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	htmlTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	htmlAttrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+)
+
+// Tags of the inline containers
+var inlineTags = map[Kind]string{
+	KindEmph:        "em",
+	KindStrong:      "strong",
+	KindMark:        "mark",
+	KindInsert:      "ins",
+	KindDelete:      "del",
+	KindSuperscript: "sup",
+	KindSubscript:   "sub",
+	KindSpan:        "span",
+}
+
+type htmlRenderer struct {
+	doc *Document
+	b   strings.Builder
+	// Footnotes are numbered in the order of the first reference
+	footnotes []string
+	numbers   map[string]int
+	tight     bool
+}
+
+// RenderHTML converts the document to HTML in the style of the reference implementation
+func RenderHTML(doc *Document) string {
+	r := &htmlRenderer{doc: doc, numbers: map[string]int{}}
+	r.blocks(doc.Children)
+	r.endnotes()
+
+	return r.b.String()
+}
+
+func (r *htmlRenderer) attrs(attrs Attributes, skip ...string) {
+	for _, attr := range attrs {
+		if !slices.Contains(skip, attr.Key) {
+			r.b.WriteString(" " + attr.Key + `="` + htmlAttrEscaper.Replace(attr.Value) + `"`)
+		}
+	}
+}
+
+func (r *htmlRenderer) open(tag string, attrs Attributes, extra ...Attribute) {
+	r.b.WriteString("<" + tag)
+
+	for _, attr := range extra {
+		if _, ok := attrs.Get(attr.Key); !ok {
+			r.b.WriteString(" " + attr.Key + `="` + htmlAttrEscaper.Replace(attr.Value) + `"`)
+		}
+	}
+
+	r.attrs(attrs)
+	r.b.WriteString(">")
+}
+
+func (r *htmlRenderer) blocks(nodes []*Node) {
+	for _, node := range nodes {
+		r.block(node)
+	}
+}
+
+func (r *htmlRenderer) block(node *Node) {
+	switch node.Kind {
+	case KindSection:
+		r.open("section", node.Attrs)
+		r.b.WriteString("\n")
+
+		for i, child := range node.Children {
+			if i == 0 && child.Kind == KindHeading {
+				r.heading(child, "id")
+			} else {
+				r.block(child)
+			}
+		}
+
+		r.b.WriteString("</section>\n")
+	case KindParagraph:
+		if r.tight {
+			r.inlines(node.Children)
+			r.b.WriteString("\n")
+
+			return
+		}
+
+		r.open("p", node.Attrs)
+		r.inlines(node.Children)
+		r.b.WriteString("</p>\n")
+	case KindHeading:
+		r.heading(node)
+	case KindThematicBreak:
+		r.open("hr", node.Attrs)
+		r.b.WriteString("\n")
+	case KindBlockQuote, KindDiv:
+		tag := map[Kind]string{KindBlockQuote: "blockquote", KindDiv: "div"}[node.Kind]
+		r.container(tag, node)
+	case KindBulletList, KindOrderedList, KindTaskList:
+		r.list(node)
+	case KindDefinitionList:
+		r.definitionList(node)
+	case KindCodeBlock:
+		r.open("pre", node.Attrs)
+
+		if node.Format != "" {
+			r.b.WriteString(`<code class="language-` + htmlAttrEscaper.Replace(node.Format) + `">`)
+		} else {
+			r.b.WriteString("<code>")
+		}
+
+		r.b.WriteString(htmlTextEscaper.Replace(node.Text) + "</code></pre>\n")
+	case KindRawBlock:
+		if node.Format == "html" {
+			r.b.WriteString(node.Text)
+		}
+	case KindTable:
+		r.table(node)
+	case KindReference, KindFootnote:
+		// Definitions are used by the content that references them
+	}
+}
+
+// Render the children of a block without the tightness of an enclosing list
+func (r *htmlRenderer) container(tag string, node *Node) {
+	tight := r.tight
+	r.tight = false
+
+	r.open(tag, node.Attrs)
+	r.b.WriteString("\n")
+	r.blocks(node.Children)
+	r.b.WriteString("</" + tag + ">\n")
+
+	r.tight = tight
+}
+
+func (r *htmlRenderer) heading(node *Node, skip ...string) {
+	tag := "h" + strconv.Itoa(node.Level)
+	r.b.WriteString("<" + tag)
+	r.attrs(node.Attrs, skip...)
+	r.b.WriteString(">")
+	r.inlines(node.Children)
+	r.b.WriteString("</" + tag + ">\n")
+}
+
+// HTML list types for the numbering of ordered list styles
+var listTypes = map[byte]string{'a': "a", 'A': "A", 'i': "i", 'I': "I"}
+
+func (r *htmlRenderer) list(node *Node) {
+	tag := "ul"
+	extra := []Attribute{}
+
+	switch node.Kind {
+	case KindOrderedList:
+		tag = "ol"
+
+		if node.StartNumber != 1 {
+			extra = append(extra, Attribute{Key: "start", Value: strconv.Itoa(node.StartNumber)})
+		}
+
+		if listType, ok := listTypes[styleNumbering(node.Style)]; ok {
+			extra = append(extra, Attribute{Key: "type", Value: listType})
+		}
+	case KindTaskList:
+		extra = append(extra, Attribute{Key: "class", Value: "task-list"})
+	}
+
+	r.open(tag, node.Attrs, extra...)
+	r.b.WriteString("\n")
+
+	tight := r.tight
+
+	for _, item := range node.Children {
+		r.tight = node.Tight
+		itemExtra := []Attribute{}
+
+		if item.Kind == KindTaskListItem {
+			class := "unchecked"
+			if item.Checked {
+				class = "checked"
+			}
+
+			itemExtra = append(itemExtra, Attribute{Key: "class", Value: class})
+		}
+
+		r.open("li", item.Attrs, itemExtra...)
+		r.b.WriteString("\n")
+		r.blocks(item.Children)
+		r.b.WriteString("</li>\n")
+	}
+
+	r.tight = tight
+	r.b.WriteString("</" + tag + ">\n")
+}
+
+func (r *htmlRenderer) definitionList(node *Node) {
+	r.open("dl", node.Attrs)
+	r.b.WriteString("\n")
+
+	for _, item := range node.Children {
+		term, definition := item.Children[0], item.Children[1]
+
+		r.open("dt", term.Attrs)
+		r.inlines(term.Children)
+		r.b.WriteString("</dt>\n")
+		r.container("dd", definition)
+	}
+
+	r.b.WriteString("</dl>\n")
+}
+
+func (r *htmlRenderer) table(node *Node) {
+	r.open("table", node.Attrs)
+	r.b.WriteString("\n")
+
+	for _, child := range node.Children {
+		if child.Kind == KindCaption {
+			r.b.WriteString("<caption>")
+			r.inlines(child.Children)
+			r.b.WriteString("</caption>\n")
+		}
+	}
+
+	for _, row := range node.Children {
+		if row.Kind != KindRow {
+			continue
+		}
+
+		r.open("tr", row.Attrs)
+		r.b.WriteString("\n")
+
+		for _, cell := range row.Children {
+			tag := "td"
+			if cell.Head {
+				tag = "th"
+			}
+
+			extra := []Attribute{}
+			if cell.Align != AlignDefault {
+				extra = append(extra, Attribute{Key: "style", Value: "text-align: " + string(cell.Align) + ";"})
+			}
+
+			r.open(tag, cell.Attrs, extra...)
+			r.inlines(cell.Children)
+			r.b.WriteString("</" + tag + ">\n")
+		}
+
+		r.b.WriteString("</tr>\n")
+	}
+
+	r.b.WriteString("</table>\n")
+}
+
+func (r *htmlRenderer) inlines(nodes []*Node) {
+	for _, node := range nodes {
+		r.inline(node)
+	}
+}
+
+func (r *htmlRenderer) inline(node *Node) {
+	switch node.Kind {
+	case KindStr:
+		if len(node.Attrs) > 0 {
+			r.open("span", node.Attrs)
+			r.b.WriteString(htmlTextEscaper.Replace(node.Text) + "</span>")
+		} else {
+			r.b.WriteString(htmlTextEscaper.Replace(node.Text))
+		}
+	case KindSoftBreak:
+		r.b.WriteString("\n")
+	case KindHardBreak:
+		r.b.WriteString("<br>\n")
+	case KindNonBreakingSpace:
+		r.b.WriteString("&nbsp;")
+	case KindVerbatim:
+		r.open("code", node.Attrs)
+		r.b.WriteString(htmlTextEscaper.Replace(node.Text) + "</code>")
+	case KindInlineMath, KindDisplayMath:
+		class, open, closing := "math inline", `\(`, `\)`
+		if node.Kind == KindDisplayMath {
+			class, open, closing = "math display", `\[`, `\]`
+		}
+
+		r.open("span", node.Attrs, Attribute{Key: "class", Value: class})
+		r.b.WriteString(open + htmlTextEscaper.Replace(node.Text) + closing + "</span>")
+	case KindRawInline:
+		if node.Format == "html" {
+			r.b.WriteString(node.Text)
+		}
+	case KindLink, KindURL, KindEmail:
+		r.link(node)
+	case KindImage:
+		attrs := r.referenceAttrs(node)
+		r.open("img", attrs, Attribute{Key: "alt", Value: node.TextContent()}, Attribute{Key: "src", Value: node.Dest})
+	case KindFootnoteReference:
+		n := r.footnoteNumber(node.Label)
+		r.b.WriteString(`<a id="fnref` + n + `" href="#fn` + n + `" role="doc-noteref"><sup>` + n + "</sup></a>")
+	case KindSymbol:
+		r.b.WriteString(":" + htmlTextEscaper.Replace(node.Text) + ":")
+	case KindDoubleQuoted, KindSingleQuoted:
+		left, right := "“", "”"
+		if node.Kind == KindSingleQuoted {
+			left, right = "‘", "’"
+		}
+
+		r.b.WriteString(left)
+		r.inlines(node.Children)
+		r.b.WriteString(right)
+	default:
+		if tag, ok := inlineTags[node.Kind]; ok {
+			r.open(tag, node.Attrs)
+			r.inlines(node.Children)
+			r.b.WriteString("</" + tag + ">")
+		} else {
+			r.b.WriteString(smartText[node.Kind])
+		}
+	}
+}
+
+// The attributes of a reference definition apply to the links that use it
+func (r *htmlRenderer) referenceAttrs(node *Node) Attributes {
+	attrs := Attributes{}
+
+	if ref, ok := r.doc.References[node.Label]; ok && node.Label != "" {
+		attrs.Merge(ref.Attrs)
+	}
+
+	attrs.Merge(node.Attrs)
+
+	return attrs
+}
+
+func (r *htmlRenderer) link(node *Node) {
+	switch node.Kind {
+	case KindURL:
+		r.open("a", node.Attrs, Attribute{Key: "href", Value: node.Text})
+		r.b.WriteString(htmlTextEscaper.Replace(node.Text) + "</a>")
+	case KindEmail:
+		r.open("a", node.Attrs, Attribute{Key: "href", Value: "mailto:" + node.Text})
+		r.b.WriteString(htmlTextEscaper.Replace(node.Text) + "</a>")
+	default:
+		extra := []Attribute{}
+		if _, ok := r.doc.References[node.Label]; ok || node.Label == "" {
+			extra = append(extra, Attribute{Key: "href", Value: node.Dest})
+		}
+
+		r.open("a", r.referenceAttrs(node), extra...)
+		r.inlines(node.Children)
+		r.b.WriteString("</a>")
+	}
+}
+
+func (r *htmlRenderer) footnoteNumber(label string) string {
+	n, ok := r.numbers[label]
+	if !ok {
+		r.footnotes = append(r.footnotes, label)
+		n = len(r.footnotes)
+		r.numbers[label] = n
+	}
+
+	return strconv.Itoa(n)
+}
+
+// Render the referenced footnotes, including the footnotes that are only referenced from other footnotes
+func (r *htmlRenderer) endnotes() {
+	if len(r.footnotes) == 0 {
+		return
+	}
+
+	r.b.WriteString("<section role=\"doc-endnotes\">\n<hr>\n<ol>\n")
+
+	for i := 0; i < len(r.footnotes); i++ {
+		n := strconv.Itoa(i + 1)
+		backlink := `<a href="#fnref` + n + `" role="doc-backlink">↩︎︎</a>`
+
+		r.b.WriteString(`<li id="fn` + n + `">` + "\n")
+
+		blocks := []*Node{}
+		if note, ok := r.doc.Footnotes[r.footnotes[i]]; ok {
+			blocks = note.Children
+		}
+
+		if len(blocks) > 0 && blocks[len(blocks)-1].Kind == KindParagraph {
+			r.blocks(blocks[:len(blocks)-1])
+
+			last := blocks[len(blocks)-1]
+			r.open("p", last.Attrs)
+			r.inlines(last.Children)
+			r.b.WriteString(backlink + "</p>\n")
+		} else {
+			r.blocks(blocks)
+			r.b.WriteString("<p>" + backlink + "</p>\n")
+		}
+
+		r.b.WriteString("</li>\n")
+	}
+
+	r.b.WriteString("</ol>\n</section>\n")
+}
@@ -0,0 +1,701 @@
+package djot
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	rawFormatRe = regexp.MustCompile(`^\{=([^\s{}]+)\}`)
+	symbolRe    = regexp.MustCompile(`^:([\w+-]+):`)
+	schemeRe    = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+)
+
+// The node that each emphasis-like delimiter creates
+var delimiterKinds = map[string]Kind{
+	"_":  KindEmph,
+	"*":  KindStrong,
+	"^":  KindSuperscript,
+	"~":  KindSubscript,
+	"=":  KindMark,
+	"+":  KindInsert,
+	"-":  KindDelete,
+	`"`:  KindDoubleQuoted,
+	"'":  KindSingleQuoted,
+	"[":  KindLink,
+	"![": KindImage,
+}
+
+// opener is a delimiter that can start an inline container when a matching closer follows
+type opener struct {
+	delim  string
+	braced bool
+	// index of the placeholder node, which is the literal text of the delimiter when there is no closer
+	index int
+	// start and end of the delimiter in the text
+	start int
+	end   int
+}
+
+type inlineParser struct {
+	s string
+	// offsets maps each byte of s to the source, with an extra entry for the end
+	offsets      []int
+	nodes        []*Node
+	openers      []opener
+	placeholders map[*Node]bool
+	i            int
+	// parenEnds is the end of the parentheses opened at each byte of s, computed on the first link destination
+	parenEnds []int
+}
+
+// Parse the lines of a block into inline nodes
+func parseInlines(segments []segment) []*Node {
+	ip := &inlineParser{placeholders: map[*Node]bool{}}
+
+	var b strings.Builder
+
+	prevEnd := 0
+
+	for k, seg := range segments {
+		text := strings.TrimRight(seg.text, " \t")
+		if k > 0 {
+			// The line break maps to the end of the previous line
+			b.WriteByte('\n')
+			ip.offsets = append(ip.offsets, prevEnd)
+		}
+
+		b.WriteString(text)
+
+		for j := range len(text) {
+			ip.offsets = append(ip.offsets, seg.offset+j)
+		}
+
+		prevEnd = seg.offset + len(text)
+	}
+
+	ip.s = b.String()
+	ip.offsets = append(ip.offsets, prevEnd)
+	ip.parse()
+
+	nodes := mergeText(ip.nodes)
+	for len(nodes) > 0 && nodes[len(nodes)-1].Kind == KindSoftBreak {
+		nodes = nodes[:len(nodes)-1]
+	}
+
+	return trimLineEnds(nodes, true)
+}
+
+// Remove whitespace and empty lines left by ignored attributes
+func trimLineEnds(nodes []*Node, block bool) []*Node {
+	kept := []*Node{}
+
+	for _, node := range nodes {
+		if node.Kind == KindSoftBreak && (len(kept) == 0 && block || len(kept) > 0 && kept[len(kept)-1].Kind == KindSoftBreak) {
+			continue
+		}
+
+		kept = append(kept, node)
+	}
+
+	nodes = kept
+
+	for i, node := range nodes {
+		switch {
+		case node.Kind == KindStr && (i+1 == len(nodes) && block || i+1 < len(nodes) && nodes[i+1].Kind == KindSoftBreak):
+			node.Text = strings.TrimRight(node.Text, " \t")
+		case len(node.Children) > 0:
+			node.Children = trimLineEnds(node.Children, false)
+		}
+	}
+
+	return mergeText(nodes)
+}
+
+func (ip *inlineParser) pos(i int) Pos {
+	return Pos{Offset: ip.offsets[i]}
+}
+
+// The position after the byte before i
+func (ip *inlineParser) endPos(i int) Pos {
+	if i == 0 {
+		return Pos{Offset: ip.offsets[0]}
+	}
+
+	return Pos{Offset: ip.offsets[i-1] + 1}
+}
+
+func (ip *inlineParser) add(kind Kind, start, end int) *Node {
+	node := &Node{Kind: kind, Start: ip.pos(start), End: ip.endPos(end)}
+	ip.nodes = append(ip.nodes, node)
+	ip.i = end
+
+	return node
+}
+
+// Add text as a new node, which is merged with the adjacent text by mergeText, since appending to the previous node
+// copies its text each time
+func (ip *inlineParser) addText(text string, start, end int) {
+	ip.add(KindStr, start, end).Text = text
+}
+
+func (ip *inlineParser) peek(offset int) byte {
+	if ip.i+offset < len(ip.s) && ip.i+offset >= 0 {
+		return ip.s[ip.i+offset]
+	}
+
+	return 0
+}
+
+func isSpecial(c byte) bool {
+	return strings.IndexByte("\\`$_*^~{[!]<\"'.-+=:\n", c) >= 0
+}
+
+func isASCIIPunct(c byte) bool {
+	return c >= '!' && c <= '/' || c >= ':' && c <= '@' || c >= '[' && c <= '`' || c >= '{' && c <= '~'
+}
+
+func isAlnum(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+func (ip *inlineParser) parse() {
+	for ip.i < len(ip.s) {
+		switch c := ip.s[ip.i]; c {
+		case '\\':
+			ip.escape()
+		case '`':
+			ip.verbatim(ip.i, KindVerbatim)
+		case '$':
+			ip.math()
+		case '_', '*', '^', '~':
+			ip.delimiter(string(c))
+		case '{':
+			ip.brace()
+		case '[':
+			ip.bracket()
+		case '!':
+			if ip.peek(1) == '[' {
+				ip.pushOpener("![", false, 2)
+			} else {
+				ip.addText("!", ip.i, ip.i+1)
+			}
+		case ']':
+			ip.closeBracket()
+		case '<':
+			ip.autolink()
+		case '"', '\'':
+			ip.quote(c)
+		case '.':
+			if strings.HasPrefix(ip.s[ip.i:], "...") {
+				ip.add(KindEllipses, ip.i, ip.i+3)
+			} else {
+				ip.addText(".", ip.i, ip.i+1)
+			}
+		case '-':
+			ip.hyphen()
+		case '+', '=':
+			if ip.peek(1) == '}' && ip.closeDelimiter(string(c), ip.i+2) {
+				continue
+			}
+
+			ip.addText(string(c), ip.i, ip.i+1)
+		case ':':
+			if m := symbolRe.FindStringSubmatch(ip.s[ip.i:]); m != nil {
+				ip.add(KindSymbol, ip.i, ip.i+len(m[0])).Text = m[1]
+			} else {
+				ip.addText(":", ip.i, ip.i+1)
+			}
+		case '\n':
+			ip.add(KindSoftBreak, ip.i, ip.i+1)
+		default:
+			end := ip.i + 1
+			for end < len(ip.s) && !isSpecial(ip.s[end]) {
+				end++
+			}
+
+			ip.addText(ip.s[ip.i:end], ip.i, end)
+		}
+	}
+
+	for _, op := range ip.openers {
+		ip.unmatched(op)
+	}
+}
+
+func (ip *inlineParser) escape() {
+	switch next := ip.peek(1); {
+	case next == '\n':
+		ip.add(KindHardBreak, ip.i, ip.i+2)
+	case next == ' ':
+		ip.add(KindNonBreakingSpace, ip.i, ip.i+2)
+	case isASCIIPunct(next):
+		ip.addText(string(next), ip.i, ip.i+2)
+	default:
+		ip.addText(`\`, ip.i, ip.i+1)
+	}
+}
+
+// Read the content of backticks starting at i, which continues to the end when there is no closer
+func (ip *inlineParser) verbatim(start int, kind Kind) {
+	n := countRun(ip.s, ip.i, '`')
+	contentStart := ip.i + n
+	contentEnd := findRun(ip.s, contentStart, '`', n)
+	end := contentEnd + n
+
+	if contentEnd < 0 {
+		contentEnd, end = len(ip.s), len(ip.s)
+	}
+
+	content := ip.s[contentStart:contentEnd]
+	if strings.HasPrefix(content, " `") {
+		content = content[1:]
+	}
+
+	if strings.HasSuffix(content, "` ") {
+		content = content[:len(content)-1]
+	}
+
+	if kind == KindVerbatim {
+		if m := rawFormatRe.FindStringSubmatch(ip.s[end:]); m != nil {
+			node := ip.add(KindRawInline, start, end+len(m[0]))
+			node.Text, node.Format = content, m[1]
+
+			return
+		}
+	}
+
+	ip.add(kind, start, end).Text = content
+}
+
+func (ip *inlineParser) math() {
+	switch {
+	case ip.peek(1) == '`':
+		start := ip.i
+		ip.i++
+		ip.verbatim(start, KindInlineMath)
+	case ip.peek(1) == '$' && ip.peek(2) == '`':
+		start := ip.i
+		ip.i += 2
+		ip.verbatim(start, KindDisplayMath)
+	default:
+		ip.addText("$", ip.i, ip.i+1)
+	}
+}
+
+func (ip *inlineParser) pushOpener(delim string, braced bool, width int) {
+	start := ip.i
+	placeholder := &Node{Kind: KindStr, Text: ip.s[start : start+width], Start: ip.pos(start), End: ip.endPos(start + width)}
+
+	ip.nodes = append(ip.nodes, placeholder)
+	ip.placeholders[placeholder] = true
+	ip.openers = append(ip.openers, opener{
+		delim: delim, braced: braced, index: len(ip.nodes) - 1, start: start, end: start + width,
+	})
+	ip.i = start + width
+}
+
+// The placeholder of an opener without a closer stays as text, except for quotes that default to a direction
+func (ip *inlineParser) unmatched(op opener) {
+	placeholder := ip.nodes[op.index]
+	delete(ip.placeholders, placeholder)
+
+	switch op.delim {
+	case `"`:
+		placeholder.Kind, placeholder.Text = KindLeftDoubleQuote, ""
+	case "'":
+		placeholder.Kind, placeholder.Text = KindRightSingleQuote, ""
+	}
+}
+
+// Find the innermost opener for the delimiter that has content before the closer at i
+func (ip *inlineParser) findOpener(delim string) int {
+	for k := len(ip.openers) - 1; k >= 0; k-- {
+		if ip.openers[k].delim == delim && ip.openers[k].end != ip.i {
+			return k
+		}
+	}
+
+	return -1
+}
+
+// Replace the opener with a container of the nodes that followed it, discarding any openers in between
+func (ip *inlineParser) closeOpener(k int, end int) *Node {
+	op := ip.openers[k]
+	for _, discarded := range ip.openers[k+1:] {
+		ip.unmatched(discarded)
+	}
+
+	children := mergeText(append([]*Node{}, ip.nodes[op.index+1:]...))
+	node := &Node{Kind: delimiterKinds[op.delim], Children: children, Start: ip.pos(op.start), End: ip.endPos(end)}
+
+	delete(ip.placeholders, ip.nodes[op.index])
+	ip.nodes = append(ip.nodes[:op.index], node)
+	ip.openers = ip.openers[:k]
+	ip.i = end
+
+	return node
+}
+
+// Close the innermost opener of the delimiter, returning false when there is none
+func (ip *inlineParser) closeDelimiter(delim string, end int) bool {
+	k := ip.findOpener(delim)
+	if k < 0 {
+		return false
+	}
+
+	ip.closeOpener(k, end)
+
+	return true
+}
+
+// Emphasis-like delimiters open before non-space and close after non-space, unless forced with braces
+func (ip *inlineParser) delimiter(delim string) {
+	forcedClose := ip.peek(1) == '}'
+	canClose := forcedClose || (ip.i > 0 && !isSpace(ip.peek(-1)))
+	canOpen := !forcedClose && ip.i+1 < len(ip.s) && !isSpace(ip.peek(1))
+
+	end := ip.i + 1
+	if forcedClose {
+		end++
+	}
+
+	switch {
+	case canClose && ip.closeDelimiter(delim, end):
+	case forcedClose:
+		ip.addText(delim+"}", ip.i, end)
+	case canOpen:
+		ip.pushOpener(delim, false, 1)
+	default:
+		ip.addText(delim, ip.i, ip.i+1)
+	}
+}
+
+func (ip *inlineParser) hyphen() {
+	if ip.peek(1) == '}' {
+		if !ip.closeDelimiter("-", ip.i+2) {
+			ip.addText("-", ip.i, ip.i+1)
+		}
+
+		return
+	}
+
+	n := countRun(ip.s, ip.i, '-')
+	if ip.i+n < len(ip.s) && ip.s[ip.i+n] == '}' && ip.findOpener("-") >= 0 {
+		n--
+	}
+
+	if n == 1 {
+		ip.addText("-", ip.i, ip.i+1)
+		return
+	}
+
+	ems, ens := dashes(n)
+
+	for range ems {
+		ip.add(KindEmDash, ip.i, ip.i+3)
+	}
+
+	for range ens {
+		ip.add(KindEnDash, ip.i, ip.i+2)
+	}
+}
+
+// Split a run of hyphens into em and en dashes, preferring dashes of the same kind
+func dashes(n int) (int, int) {
+	switch {
+	case n%3 == 0:
+		return n / 3, 0
+	case n%2 == 0:
+		return 0, n / 2
+	case n%3 == 2:
+		return n / 3, 1
+	default:
+		return (n - 4) / 3, 2
+	}
+}
+
+func (ip *inlineParser) brace() {
+	next := ip.peek(1)
+	if next != 0 && strings.IndexByte("_*^~=+-\"'", next) >= 0 {
+		ip.pushOpener(string(next), true, 2)
+		return
+	}
+
+	attrs, end, status := parseAttributes(ip.s, ip.i)
+	if status != attrsDone {
+		ip.addText("{", ip.i, ip.i+1)
+		return
+	}
+
+	ip.attach(attrs, end)
+}
+
+// Attributes apply to the preceding element, or the preceding word, and are ignored after whitespace
+func (ip *inlineParser) attach(attrs Attributes, end int) {
+	start := ip.i
+	ip.i = end
+
+	if len(ip.nodes) == 0 || start == 0 || isSpace(ip.s[start-1]) {
+		return
+	}
+
+	// The word is found in the text after the last placeholder or other node
+	first := len(ip.nodes)
+	for first > 0 && ip.nodes[first-1].Kind == KindStr && !ip.placeholders[ip.nodes[first-1]] {
+		first--
+	}
+
+	ip.nodes = append(ip.nodes[:first], mergeText(ip.nodes[first:])...)
+	if len(ip.nodes) == 0 {
+		return
+	}
+
+	last := ip.nodes[len(ip.nodes)-1]
+
+	switch {
+	case ip.placeholders[last]:
+		return
+	case last.Kind != KindStr:
+		last.Attrs.Merge(attrs)
+		return
+	}
+
+	word := last.Text[strings.LastIndexAny(last.Text, " \t")+1:]
+	if word == "" {
+		return
+	}
+
+	wordStart := Pos{Offset: last.End.Offset - len(word)}
+	span := &Node{
+		Kind:     KindSpan,
+		Attrs:    attrs,
+		Start:    wordStart,
+		End:      last.End,
+		Children: []*Node{{Kind: KindStr, Text: word, Start: wordStart, End: last.End}},
+	}
+
+	if len(word) == len(last.Text) {
+		ip.nodes[len(ip.nodes)-1] = span
+	} else {
+		last.Text = last.Text[:len(last.Text)-len(word)]
+		last.End = wordStart
+		ip.nodes = append(ip.nodes, span)
+	}
+}
+
+func (ip *inlineParser) bracket() {
+	if ip.peek(1) == '^' {
+		if end := strings.IndexAny(ip.s[ip.i+2:], "[]"); end > 0 && ip.s[ip.i+2+end] == ']' {
+			label := ip.s[ip.i+2 : ip.i+2+end]
+			ip.add(KindFootnoteReference, ip.i, ip.i+3+end).Label = label
+
+			return
+		}
+	}
+
+	ip.pushOpener("[", false, 1)
+}
+
+func (ip *inlineParser) closeBracket() {
+	k := len(ip.openers) - 1
+	for k >= 0 && ip.openers[k].delim != "[" && ip.openers[k].delim != "![" {
+		k--
+	}
+
+	if k < 0 {
+		ip.addText("]", ip.i, ip.i+1)
+		return
+	}
+
+	op := ip.openers[k]
+	after := ip.i + 1
+
+	switch ip.peekAt(after) {
+	case '(':
+		if ip.parenEnds == nil {
+			ip.parenEnds = matchParens(ip.s)
+		}
+
+		// An unclosed destination isn't scanned, since it would be scanned to the end for each bracket
+		if ip.parenEnds[after] == 0 {
+			break
+		}
+
+		if dest, end, ok := scanDestination(ip.s, after); ok {
+			ip.closeOpener(k, end).Dest = dest
+			return
+		}
+	case '[':
+		if end := strings.IndexByte(ip.s[after+1:], ']'); end >= 0 {
+			label := ip.s[after+1 : after+1+end]
+			if label == "" {
+				label = ip.s[op.end:ip.i]
+			}
+
+			ip.closeOpener(k, after+2+end).Label = normalizeLabel(label)
+
+			return
+		}
+	case '{':
+		if op.delim == "[" {
+			if attrs, end, status := parseAttributes(ip.s, after); status == attrsDone {
+				node := ip.closeOpener(k, end)
+				node.Kind, node.Attrs = KindSpan, attrs
+
+				return
+			}
+		}
+	}
+
+	// Not a link, so the brackets are literal text
+	delete(ip.placeholders, ip.nodes[op.index])
+	ip.openers = append(ip.openers[:k], ip.openers[k+1:]...)
+	ip.addText("]", ip.i, ip.i+1)
+}
+
+func (ip *inlineParser) peekAt(i int) byte {
+	if i < len(ip.s) {
+		return ip.s[i]
+	}
+
+	return 0
+}
+
+// The index after the matching closing parenthesis of each opening parenthesis, which is 0 for other bytes and when
+// there is no match, where escaped parentheses are skipped like in scanDestination
+func matchParens(s string) []int {
+	ends := make([]int, len(s))
+	opened := []int{}
+
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s) && isASCIIPunct(s[i+1]):
+			i++
+		case c == '(':
+			opened = append(opened, i)
+		case c == ')' && len(opened) > 0:
+			ends[opened[len(opened)-1]] = i + 1
+			opened = opened[:len(opened)-1]
+		}
+	}
+
+	return ends
+}
+
+// Read a link destination in parentheses, where line breaks are removed and parentheses can be nested
+func scanDestination(s string, start int) (string, int, bool) {
+	var b strings.Builder
+
+	depth := 0
+
+	for i := start; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s) && isASCIIPunct(s[i+1]):
+			i++
+			b.WriteByte(s[i])
+		case c == '(':
+			depth++
+			if depth > 1 {
+				b.WriteByte(c)
+			}
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return b.String(), i + 1, true
+			}
+
+			b.WriteByte(c)
+		case c == '\n':
+			for i+1 < len(s) && (s[i+1] == ' ' || s[i+1] == '\t') {
+				i++
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return "", start, false
+}
+
+func (ip *inlineParser) autolink() {
+	end := strings.IndexAny(ip.s[ip.i+1:], "<> \t\n")
+	if end > 0 && ip.s[ip.i+1+end] == '>' {
+		content := ip.s[ip.i+1 : ip.i+1+end]
+
+		switch {
+		case schemeRe.MatchString(content):
+			ip.add(KindURL, ip.i, ip.i+2+end).Text = content
+			return
+		case strings.Contains(content, "@"):
+			ip.add(KindEmail, ip.i, ip.i+2+end).Text = content
+			return
+		}
+	}
+
+	ip.addText("<", ip.i, ip.i+1)
+}
+
+// Quotes are matched like delimiters. An apostrophe follows a letter and unmatched quotes default to a direction
+func (ip *inlineParser) quote(c byte) {
+	delim := string(c)
+	forcedClose := ip.peek(1) == '}'
+	canClose := forcedClose || (ip.i > 0 && !isSpace(ip.peek(-1)))
+	canOpen := !forcedClose && ip.i+1 < len(ip.s) && !isSpace(ip.peek(1))
+
+	end := ip.i + 1
+	if forcedClose {
+		end++
+	}
+
+	switch {
+	case canClose && ip.closeDelimiter(delim, end):
+	case c == '\'' && ip.i > 0 && isAlnum(ip.peek(-1)):
+		ip.add(KindRightSingleQuote, ip.i, end)
+	case canOpen:
+		ip.pushOpener(delim, false, 1)
+	case c == '\'':
+		ip.add(KindRightSingleQuote, ip.i, end)
+	case canClose:
+		ip.add(KindRightDoubleQuote, ip.i, end)
+	default:
+		ip.add(KindLeftDoubleQuote, ip.i, end)
+	}
+}
+
+// Combine adjacent text nodes, including the literal text of unmatched delimiters
+func mergeText(nodes []*Node) []*Node {
+	merged := []*Node{}
+
+	for i := 0; i < len(nodes); i++ {
+		node := nodes[i]
+		if node.Kind != KindStr {
+			merged = append(merged, node)
+			continue
+		}
+
+		if node.Text == "" {
+			continue
+		}
+
+		// The run of text is joined at once, since joining one node at a time copies the text for each node
+		end := i + 1
+		for end < len(nodes) && nodes[end].Kind == KindStr {
+			end++
+		}
+
+		if end > i+1 {
+			var b strings.Builder
+			for _, str := range nodes[i:end] {
+				b.WriteString(str.Text)
+			}
+
+			node = &Node{Kind: KindStr, Text: b.String(), Start: node.Start, End: nodes[end-1].End}
+		}
+
+		merged = append(merged, node)
+		i = end - 1
+	}
+
+	return merged
+}
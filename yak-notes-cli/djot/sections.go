@@ -0,0 +1,123 @@
+package djot
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Characters that are removed from the text of a heading to generate the id, including typographic quotes so that
+// the id is the same however quotes are typed
+const idPunctuation = "][~!@#$%^&*(){}`,.<>\\|=+/?\"'“”‘’"
+
+// The text of smart punctuation in the ids and reference labels of headings, where quotes are kept as typed so that
+// they are removed from ids like the rest of the punctuation
+var headingSmartText = map[Kind]string{
+	KindLeftDoubleQuote:  `"`,
+	KindRightDoubleQuote: `"`,
+	KindLeftSingleQuote:  "'",
+	KindRightSingleQuote: "'",
+	KindEllipses:         "…",
+	KindEnDash:           "–",
+	KindEmDash:           "—",
+}
+
+// Group the top-level blocks into nested sections, resolve references, and convert offsets to positions
+func (p *parser) finish() {
+	ids := map[string]bool{}
+
+	Walk(p.doc.Node, func(node *Node) bool {
+		if id, ok := node.Attrs.Get("id"); ok {
+			ids[id] = true
+		}
+
+		return true
+	})
+
+	p.doc.Children = p.sections(p.doc.Children, ids)
+
+	Walk(p.doc.Node, func(node *Node) bool {
+		if (node.Kind == KindLink || node.Kind == KindImage) && node.Label != "" {
+			if ref, ok := p.doc.References[node.Label]; ok {
+				node.Dest = ref.Dest
+			}
+		}
+
+		node.Start = p.doc.Position(node.Start.Offset)
+		node.End = p.doc.Position(node.End.Offset)
+
+		return true
+	})
+}
+
+// HeadingID generates an id from the text of a heading that is unique among the used ids
+func HeadingID(text string, used map[string]bool) string {
+	text = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(idPunctuation, r) {
+			return -1
+		}
+
+		return r
+	}, text)
+
+	base := strings.Join(strings.Fields(text), "-")
+	if base == "" {
+		base = "s"
+	}
+
+	id := base
+	for n := 1; used[id]; n++ {
+		id = base + "-" + strconv.Itoa(n)
+	}
+
+	used[id] = true
+
+	return id
+}
+
+// Wrap each heading and the blocks that follow it in a section, which ends at the next heading of the same level
+func (p *parser) sections(blocks []*Node, ids map[string]bool) []*Node {
+	result := []*Node{}
+	open := []*Node{}
+
+	for _, node := range blocks {
+		if node.Kind == KindHeading {
+			for len(open) > 0 && open[len(open)-1].Level >= node.Level {
+				open = open[:len(open)-1]
+			}
+
+			id, ok := node.Attrs.Get("id")
+			if !ok {
+				id = HeadingID(node.textContent(headingSmartText), ids)
+			}
+
+			section := &Node{Kind: KindSection, Level: node.Level, Start: node.Start, End: node.End}
+			section.Attrs.Set("id", id)
+
+			label := normalizeLabel(node.textContent(headingSmartText))
+			if _, exists := p.doc.References[label]; !exists && label != "" {
+				p.doc.References[label] = &Node{Kind: KindReference, Label: label, Dest: "#" + id}
+			}
+
+			if len(open) > 0 {
+				parent := open[len(open)-1]
+				parent.Children = append(parent.Children, section)
+			} else {
+				result = append(result, section)
+			}
+
+			open = append(open, section)
+		}
+
+		if len(open) > 0 {
+			open[len(open)-1].Children = append(open[len(open)-1].Children, node)
+		} else {
+			result = append(result, node)
+		}
+
+		for _, section := range open {
+			section.End = node.End
+		}
+	}
+
+	return result
+}
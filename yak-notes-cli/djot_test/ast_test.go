@@ -0,0 +1,124 @@
+package djot_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/djot"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findKind(doc *djot.Document, kind djot.Kind) []*djot.Node {
+	found := []*djot.Node{}
+
+	djot.Walk(doc.Node, func(node *djot.Node) bool {
+		if node.Kind == kind {
+			found = append(found, node)
+		}
+
+		return true
+	})
+
+	return found
+}
+
+func TestParsePositions(t *testing.T) {
+	doc := djot.Parse("# Title\n\nSome *strong*\ntext\n\n- item\n")
+
+	headings := findKind(doc, djot.KindHeading)
+	require.Len(t, headings, 1)
+	assert.Equal(t, djot.Pos{Line: 1, Col: 1, Offset: 0}, headings[0].Start)
+
+	strong := findKind(doc, djot.KindStrong)
+	require.Len(t, strong, 1)
+	assert.Equal(t, djot.Pos{Line: 3, Col: 6, Offset: 14}, strong[0].Start)
+	assert.Equal(t, djot.Pos{Line: 3, Col: 14, Offset: 22}, strong[0].End)
+	assert.Equal(t, "strong", strong[0].TextContent())
+
+	paragraphs := findKind(doc, djot.KindParagraph)
+	require.Len(t, paragraphs, 2)
+	assert.Equal(t, 3, paragraphs[0].Start.Line)
+	assert.Equal(t, 4, paragraphs[0].End.Line)
+
+	items := findKind(doc, djot.KindListItem)
+	require.Len(t, items, 1)
+	assert.Equal(t, 6, items[0].Start.Line)
+}
+
+// Parsing takes linear time, so deeply nested or long input doesn't stall, which took seconds for these inputs
+func TestParseDeepNesting(t *testing.T) {
+	inputs := map[string]string{
+		"nested lists":       strings.Repeat("- ", 16000) + "a\n" + strings.Repeat(" ", 40000) + "b",
+		"nested quotes":      strings.Repeat("> ", 16000) + "a",
+		"unclosed links":     strings.Repeat("[a](", 16000),
+		"unclosed images":    strings.Repeat("![a](", 16000),
+		"unmatched emphasis": strings.Repeat("*a ", 16000),
+		"unclosed attrs":     strings.Repeat("{.a ", 16000),
+		"text runs":          strings.Repeat("a.", 32000),
+	}
+
+	for name, input := range inputs {
+		t.Run(name, func(t *testing.T) {
+			done := make(chan *djot.Document)
+
+			go func() { done <- djot.Parse(input) }()
+
+			select {
+			case doc := <-done:
+				assert.NotEmpty(t, doc.Children)
+			case <-time.After(2 * time.Second):
+				t.Fatal("parsing took more than 2s")
+			}
+		})
+	}
+}
+
+func TestParseReferencesAndFootnotes(t *testing.T) {
+	doc := djot.Parse("[a][some  label] and [^note]\n\n[some label]: /url\n\n[^note]: Text\n")
+
+	assert.Contains(t, doc.References, "some label")
+	assert.Contains(t, doc.Footnotes, "note")
+
+	links := findKind(doc, djot.KindLink)
+	require.Len(t, links, 1)
+	assert.Equal(t, "/url", links[0].Dest)
+}
+
+func TestHeadingID(t *testing.T) {
+	used := map[string]bool{}
+
+	assert.Equal(t, "Hello-world", djot.HeadingID("Hello, world!", used))
+	assert.Equal(t, "Hello-world-1", djot.HeadingID("Hello world", used))
+	assert.Equal(t, "s", djot.HeadingID("?!", used))
+}
+
+func TestRenderDjotEscapes(t *testing.T) {
+	parameters := []string{
+		"\\*literal\\* and \\_under\\_ and a\\|b\n",
+		"\\# not a heading\n",
+		"\\- not a list\n",
+		"1\\. not a list\n",
+		"a\\-\\-b and a\\.\\.\\.b\n",
+		"\\:smile\\: and \\[brackets\\]\n",
+		"para\n\\:::\n",
+		"[link](/a\\(b) and `` a`b ``\n",
+		"- a\n\n* b\n",
+		"{_ spaced _} and {=mark=}\n",
+	}
+
+	for _, content := range parameters {
+		doc := djot.Parse(content)
+		formatted := djot.RenderDjot(doc)
+
+		assert.Equal(t, djot.RenderHTML(doc), djot.RenderHTML(djot.Parse(formatted)), formatted)
+	}
+}
+
+func TestRenderDjotCanonical(t *testing.T) {
+	content := "#  Title  \n\n* one\n* two\n\n\n``` go\nx\n```\n"
+	expected := "# Title\n\n- one\n- two\n\n``` go\nx\n```\n"
+
+	assert.Equal(t, expected, djot.RenderDjot(djot.Parse(content)))
+}
@@ -0,0 +1,33 @@
+package djot_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/djot"
+)
+
+// Rendering back to djot keeps the meaning of any document
+func FuzzRoundTrip(f *testing.F) {
+	for _, seed := range []string{
+		"|-|", "a<{'", "\"unmatched", "'single", "# Title\n\n- a\n- b\n", "``` go\ncode\n```\n",
+		"[link](url){.c}", "| a | b |\n|---|:-:|\n| 1 | 2 |\n", "{_ x _}", "a\\\nb", "[^n]\n\n[^n]: note\n",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		// Control characters other than tabs and line feeds aren't whitespace, so they can't be written back where
+		// whitespace is trimmed, and a carriage return at the end of a line becomes part of the line ending
+		if strings.IndexFunc(input, func(r rune) bool { return r < ' ' && r != '\t' && r != '\n' }) >= 0 {
+			t.Skip()
+		}
+
+		expected := djot.RenderHTML(djot.Parse(input))
+		formatted := djot.RenderDjot(djot.Parse(input))
+
+		if actual := djot.RenderHTML(djot.Parse(formatted)); actual != expected {
+			t.Errorf("input %q\nformatted %q\nexpected %q\nactual %q", input, formatted, expected, actual)
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package djot_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/djot"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type specExample struct {
+	name     string
+	input    string
+	expected string
+}
+
+// Read the examples of a test file in the format of the djot test suite, skipping examples with options
+func readSpecExamples(t *testing.T, path string) []specExample {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	examples := []specExample{}
+	lines := strings.Split(string(content), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		fence := lines[i][:len(lines[i])-len(strings.TrimLeft(lines[i], "`"))]
+		if len(fence) < 3 {
+			continue
+		}
+
+		line := i + 1
+		options := strings.TrimSpace(lines[i][len(fence):])
+
+		var input, expected strings.Builder
+
+		inExpected := false
+
+		for i++; i < len(lines) && lines[i] != fence; i++ {
+			switch {
+			case !inExpected && lines[i] == ".":
+				inExpected = true
+			case inExpected:
+				expected.WriteString(lines[i] + "\n")
+			default:
+				input.WriteString(lines[i] + "\n")
+			}
+		}
+
+		if options == "" {
+			name := fmt.Sprintf("%s:%d", filepath.Base(path), line)
+			examples = append(examples, specExample{name: name, input: input.String(), expected: expected.String()})
+		}
+	}
+
+	return examples
+}
+
+// Read the `file:line reason` entries of the upstream examples that are known to fail
+func readSkipList(t *testing.T) map[string]string {
+	t.Helper()
+
+	content, err := os.ReadFile(filepath.Join("testdata", "upstream", "skip.txt"))
+	require.NoError(t, err)
+
+	skip := map[string]string{}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		if line = strings.TrimSpace(line); line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, reason, _ := strings.Cut(line, " ")
+		skip[name] = strings.TrimSpace(reason)
+	}
+
+	return skip
+}
+
+// The examples of the local test files and of the vendored upstream test suite, which is required because the local
+// examples were written with the parser and don't show that it follows the spec
+func specExamples(t *testing.T) (local, upstream []specExample) {
+	t.Helper()
+
+	localPaths, err := filepath.Glob(filepath.Join("testdata", "local", "*.test"))
+	require.NoError(t, err)
+	require.NotEmpty(t, localPaths)
+
+	upstreamPaths, err := filepath.Glob(filepath.Join("testdata", "upstream", "*.test"))
+	require.NoError(t, err)
+	require.NotEmpty(t, upstreamPaths, "the djot.js test suite is not vendored, run `mise run djot:vendor-tests`")
+
+	for _, name := range []string{"LICENSE", "COMMIT"} {
+		require.FileExists(t, filepath.Join("testdata", "upstream", name), "run `mise run djot:vendor-tests`")
+	}
+
+	for _, path := range localPaths {
+		local = append(local, readSpecExamples(t, path)...)
+	}
+
+	for _, path := range upstreamPaths {
+		upstream = append(upstream, readSpecExamples(t, path)...)
+	}
+
+	return local, upstream
+}
+
+func TestSpecHTML(t *testing.T) {
+	local, upstream := specExamples(t)
+	skip := readSkipList(t)
+
+	for _, example := range local {
+		t.Run(example.name, func(t *testing.T) {
+			assert.Equal(t, example.expected, djot.RenderHTML(djot.Parse(example.input)), example.input)
+		})
+	}
+
+	for _, example := range upstream {
+		t.Run("upstream/"+example.name, func(t *testing.T) {
+			actual := djot.RenderHTML(djot.Parse(example.input))
+
+			if reason, ok := skip[example.name]; ok {
+				if actual == example.expected {
+					t.Errorf("%s passes, so remove it from skip.txt", example.name)
+				}
+
+				t.Skip(reason)
+			}
+
+			assert.Equal(t, example.expected, actual, example.input)
+		})
+	}
+}
+
+// Rendering back to djot keeps the meaning of the document and is stable
+func TestSpecRoundTrip(t *testing.T) {
+	local, upstream := specExamples(t)
+	skip := readSkipList(t)
+
+	for _, example := range append(local, upstream...) {
+		if _, ok := skip[example.name]; ok {
+			continue
+		}
+
+		t.Run(example.name, func(t *testing.T) {
+			formatted := djot.RenderDjot(djot.Parse(example.input))
+
+			assert.Equal(t, example.expected, djot.RenderHTML(djot.Parse(formatted)), formatted)
+			assert.Equal(t, formatted, djot.RenderDjot(djot.Parse(formatted)))
+		})
+	}
+}
+
+// Every entry of the skip list is an upstream example, so that the list doesn't keep stale entries
+func TestSpecSkipList(t *testing.T) {
+	_, upstream := specExamples(t)
+	skip := readSkipList(t)
+
+	names := map[string]bool{}
+	for _, example := range upstream {
+		names[example.name] = true
+	}
+
+	for name := range skip {
+		assert.True(t, names[name], "%s in skip.txt is not an upstream example", name)
+	}
+}
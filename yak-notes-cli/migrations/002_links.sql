@@ -0,0 +1,11 @@
+-- sqlfluff:dialect:duckdb
+-- Note: source and target are vault-relative `subDir/filename` references
+
+-- +geese up
+CREATE TABLE link (
+    source VARCHAR NOT NULL,
+    target VARCHAR NOT NULL
+);
+
+-- +geese down
+DROP TABLE IF EXISTS link;
@@ -0,0 +1,8 @@
+-- sqlfluff:dialect:duckdb
+-- Note: the title is derived from the first heading, the `name` header, or the first line
+
+-- +geese up
+ALTER TABLE note ADD COLUMN title VARCHAR DEFAULT '';
+
+-- +geese down
+ALTER TABLE note DROP COLUMN title;
@@ -0,0 +1,125 @@
+package notes
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// AgendaCategory groups tasks by how their schedule relates to today
+type AgendaCategory string
+
+const (
+	AgendaOverdue   AgendaCategory = "overdue"
+	AgendaDueToday  AgendaCategory = "due-today"
+	AgendaStartable AgendaCategory = "startable"
+	AgendaUpcoming  AgendaCategory = "upcoming"
+)
+
+var agendaOrder = []AgendaCategory{AgendaOverdue, AgendaDueToday, AgendaStartable, AgendaUpcoming}
+
+// OpenStates are the task states shown in the agenda, ordered by priority
+var OpenStates = []State{StateInProgress, StateQueue, StateBacklog}
+
+type AgendaItem struct {
+	Stat     FileStat
+	State    State
+	Schedule Schedule
+	Category AgendaCategory
+}
+
+// Truncate to the calendar date without converting between time zones
+func calendarDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func categorize(s Schedule, today, horizon time.Time) (AgendaCategory, bool) {
+	if deadline := s.Deadline(); !deadline.IsZero() {
+		switch day := calendarDate(deadline); {
+		case day.Before(today):
+			return AgendaOverdue, true
+		case day.Equal(today):
+			return AgendaDueToday, true
+		}
+	}
+
+	if !s.Start.IsZero() && !calendarDate(s.Start).After(today) {
+		return AgendaStartable, true
+	}
+
+	for _, t := range []time.Time{s.Start, s.Deadline()} {
+		if !t.IsZero() && !calendarDate(t).After(horizon) {
+			return AgendaUpcoming, true
+		}
+	}
+
+	return "", false
+}
+
+// The date used to sort items within a category
+func (item AgendaItem) sortDate() time.Time {
+	if deadline := item.Schedule.Deadline(); !deadline.IsZero() {
+		return deadline
+	}
+
+	return item.Schedule.Start
+}
+
+// Agenda returns the open tasks with a schedule that is relevant between today and the horizon in days
+//
+// Notes with an invalid state or schedule are skipped and listed in the warnings
+func (v *Vault) Agenda(now time.Time, days int) (items []AgendaItem, warnings []string, err error) {
+	stats, err := v.ListNotes("")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	today := calendarDate(now)
+	horizon := today.AddDate(0, 0, days)
+	items = []AgendaItem{}
+	warnings = []string{}
+
+	for _, stat := range stats {
+		doc, err := ReadDocument(stat.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		state, err := doc.Header.State()
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("skipped %s: %s", v.RelPath(stat.Path), err))
+			continue
+		}
+
+		if !slices.Contains(OpenStates, state) {
+			continue
+		}
+
+		schedule, err := doc.Header.Schedule()
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("skipped %s: invalid schedule: %s", v.RelPath(stat.Path), err))
+			continue
+		}
+
+		if category, ok := categorize(schedule, today, horizon); ok {
+			items = append(items, AgendaItem{Stat: stat, State: state, Schedule: schedule, Category: category})
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.Category != b.Category {
+			return slices.Index(agendaOrder, a.Category) < slices.Index(agendaOrder, b.Category)
+		}
+
+		if a.State != b.State {
+			return slices.Index(OpenStates, a.State) < slices.Index(OpenStates, b.State)
+		}
+
+		return a.sortDate().Before(b.sortDate())
+	})
+
+	return items, warnings, nil
+}
@@ -0,0 +1,222 @@
+package notes
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Hidden folders in the sync directory for removed notes, which mirror the original subDirs
+const (
+	ArchiveDir = ".archive"
+	TrashDir   = ".trash"
+)
+
+// Header keys that record when a note was removed
+const (
+	KeyArchivedAt = "archived-at"
+	KeyTrashedAt  = "trashed-at"
+)
+
+var ErrNotRemoved = errors.New("note is not archived or trashed")
+
+var removedDirs = map[string]string{ArchiveDir: KeyArchivedAt, TrashDir: KeyTrashedAt}
+
+// Rewrite the note at toPath without overwriting, then delete the original
+func moveDocument(fromPath, toPath string, doc Document) error {
+	if err := os.MkdirAll(filepath.Dir(toPath), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", toPath, err)
+	}
+
+	if err := writeFileExclusive(toPath, []byte(doc.String())); err != nil {
+		return err
+	}
+
+	if err := os.Remove(fromPath); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", fromPath, err)
+	}
+
+	return nil
+}
+
+// Move a note from its subDir into the removed folder
+//
+// Links from and to the note are kept so that restoring is lossless. Links to the note are removed when purged
+func (v *Vault) remove(path, dir string) (string, error) {
+	rel := v.RelPath(path)
+	if rel == path || strings.HasPrefix(rel, ".") {
+		return "", fmt.Errorf("%w: %s is not in a subDir", ErrInvalidName, path)
+	}
+
+	doc, err := ReadDocument(path)
+	if err != nil {
+		return "", err
+	}
+
+	doc.Header.SetTime(removedDirs[dir], time.Now())
+
+	toPath := filepath.Join(v.SyncDir, dir, filepath.FromSlash(rel))
+	if err := moveDocument(path, toPath, doc); err != nil {
+		return "", fmt.Errorf("failed to move %s to %s: %w", rel, dir, err)
+	}
+
+	return toPath, nil
+}
+
+// Archive moves the note into the hidden archive folder and returns the new path
+func (v *Vault) Archive(path string) (string, error) {
+	return v.remove(path, ArchiveDir)
+}
+
+// Trash moves the note into the hidden trash folder and returns the new path
+func (v *Vault) Trash(path string) (string, error) {
+	return v.remove(path, TrashDir)
+}
+
+// The removed folder and the `subDir/filename` of a note in the archive or trash
+func (v *Vault) splitRemoved(path string) (string, string, bool) {
+	dir, rel, found := strings.Cut(v.RelPath(path), "/")
+	if _, ok := removedDirs[dir]; !ok || !found {
+		return "", "", false
+	}
+
+	return dir, rel, true
+}
+
+// ListRemoved returns the notes in the archive or trash folder
+func (v *Vault) ListRemoved(dir string) ([]FileStat, error) {
+	stats := []FileStat{}
+	root := filepath.Join(v.SyncDir, dir)
+
+	subDirs, err := os.ReadDir(root)
+	if errors.Is(err, os.ErrNotExist) {
+		return stats, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", root, err)
+	}
+
+	for _, subDir := range subDirs {
+		if !subDir.IsDir() {
+			continue
+		}
+
+		subStats, err := v.listSubDirNotes(filepath.Join(dir, subDir.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		stats = append(stats, subStats...)
+	}
+
+	return stats, nil
+}
+
+// ResolveRemoved finds an archived or trashed note from a path, `subDir/filename`, or filename
+func (v *Vault) ResolveRemoved(ref string) (FileStat, error) {
+	if _, _, ok := v.splitRemoved(ref); ok && exists(ref) {
+		return v.statNote(ref)
+	}
+
+	name := filepath.ToSlash(ref)
+	if !IsNoteFile(name) {
+		name += NoteExt
+	}
+
+	for _, dir := range []string{ArchiveDir, TrashDir} {
+		stats, err := v.ListRemoved(dir)
+		if err != nil {
+			return FileStat{}, err
+		}
+
+		for _, stat := range stats {
+			if _, rel, _ := v.splitRemoved(stat.Path); rel == name || stat.Name == name {
+				return stat, nil
+			}
+		}
+	}
+
+	return FileStat{}, fmt.Errorf("%w: %s", ErrNoteNotFound, ref)
+}
+
+// Restore moves an archived or trashed note back to its original subDir and returns the new path
+func (v *Vault) Restore(path string) (string, error) {
+	dir, rel, ok := v.splitRemoved(path)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNotRemoved, v.RelPath(path))
+	}
+
+	toPath := v.AbsPath(rel)
+	if err := v.checkSubDir(filepath.Base(filepath.Dir(toPath))); err != nil {
+		return "", err
+	}
+
+	doc, err := ReadDocument(path)
+	if err != nil {
+		return "", err
+	}
+
+	doc.Header.Delete(removedDirs[dir])
+
+	if err := moveDocument(path, toPath, doc); err != nil {
+		return "", fmt.Errorf("failed to restore %s: %w", rel, err)
+	}
+
+	return toPath, nil
+}
+
+// PurgeTrash deletes trashed notes that are older than the retention and returns their paths
+//
+// The modification time is used when the note doesn't record when it was trashed
+func (v *Vault) PurgeTrash(now time.Time, retention time.Duration) ([]string, error) {
+	stats, err := v.ListRemoved(TrashDir)
+	if err != nil {
+		return nil, err
+	}
+
+	purged := []string{}
+
+	for _, stat := range stats {
+		trashedAt := stat.ModTime
+
+		doc, err := ReadDocument(stat.Path)
+		if err != nil {
+			return purged, err
+		}
+
+		if t, ok, err := doc.Header.GetTime(KeyTrashedAt); err == nil && ok {
+			trashedAt = t
+		}
+
+		if now.Sub(trashedAt) < retention {
+			continue
+		}
+
+		if err := os.Remove(stat.Path); err != nil {
+			return purged, fmt.Errorf("failed to purge %s: %w", v.RelPath(stat.Path), err)
+		}
+
+		purged = append(purged, stat.Path)
+	}
+
+	return purged, nil
+}
+
+// PlanPurgedUnlinks plans the removal of links to the purged notes, unless a note was recreated at the original path
+func (v *Vault) PlanPurgedUnlinks(purged []string) (Plan, error) {
+	deleted := map[string]bool{}
+
+	for _, path := range purged {
+		if _, rel, ok := v.splitRemoved(path); ok && !exists(v.AbsPath(rel)) {
+			deleted[v.AbsPath(rel)] = true
+		}
+	}
+
+	if len(deleted) == 0 {
+		return Plan{}, nil
+	}
+
+	return v.planUnlinks(deleted)
+}
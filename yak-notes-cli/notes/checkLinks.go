@@ -0,0 +1,170 @@
+package notes
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+)
+
+// Headers that record where the content of a removed note can now be found
+var successorKeys = []string{KeyMergedFrom, KeyMovedFrom, KeySplitFrom}
+
+const maxSuccessorDepth = 10
+
+// BrokenLink is a note link where the target file does not exist
+type BrokenLink struct {
+	Source string
+	Target string
+	// Candidates are the notes that record the target in their merged-from, moved-from, or split-from header
+	Candidates []string
+}
+
+// Fix is the replacement target when exactly one candidate is known
+func (b BrokenLink) Fix() (string, bool) {
+	if len(b.Candidates) == 1 {
+		return b.Candidates[0], true
+	}
+
+	return "", false
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return !errors.Is(err, os.ErrNotExist)
+}
+
+// Map each vault-relative reference from successorKeys to the notes that contain it
+func (v *Vault) successors(docs map[string]Document) map[string][]string {
+	successors := map[string][]string{}
+
+	for path, doc := range docs {
+		ref := v.RelPath(path)
+		for _, key := range successorKeys {
+			for _, previous := range doc.Header.GetList(key) {
+				if !slices.Contains(successors[previous], ref) {
+					successors[previous] = append(successors[previous], ref)
+				}
+			}
+		}
+	}
+
+	for _, refs := range successors {
+		sort.Strings(refs)
+	}
+
+	return successors
+}
+
+// Follow the successors of a missing note until existing notes are found
+func (v *Vault) resolveSuccessors(ref string, successors map[string][]string) []string {
+	resolved := []string{}
+	queue := []string{ref}
+	seen := map[string]bool{ref: true}
+
+	for depth := 0; len(queue) > 0 && depth < maxSuccessorDepth; depth++ {
+		next := []string{}
+
+		for _, current := range queue {
+			for _, candidate := range successors[current] {
+				if seen[candidate] {
+					continue
+				}
+
+				seen[candidate] = true
+
+				if exists(v.AbsPath(candidate)) {
+					resolved = append(resolved, candidate)
+				} else {
+					next = append(next, candidate)
+				}
+			}
+		}
+
+		queue = next
+	}
+
+	return resolved
+}
+
+func (v *Vault) readAllDocuments() (map[string]Document, error) {
+	stats, err := v.ListNotes("")
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make(map[string]Document, len(stats))
+
+	for _, stat := range stats {
+		doc, err := ReadDocument(stat.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		docs[stat.Path] = doc
+	}
+
+	return docs, nil
+}
+
+// CheckLinks finds every link to a missing note and the candidates that replaced it
+func (v *Vault) CheckLinks() ([]BrokenLink, error) {
+	docs, err := v.readAllDocuments()
+	if err != nil {
+		return nil, err
+	}
+
+	successors := v.successors(docs)
+	broken := []BrokenLink{}
+
+	for path, doc := range docs {
+		source := v.RelPath(path)
+		seen := map[string]bool{}
+
+		for _, target := range v.NoteLinks(path, doc) {
+			if seen[target] || exists(v.AbsPath(target)) {
+				continue
+			}
+
+			seen[target] = true
+			broken = append(broken, BrokenLink{
+				Source:     source,
+				Target:     target,
+				Candidates: v.resolveSuccessors(target, successors),
+			})
+		}
+	}
+
+	sort.Slice(broken, func(i, j int) bool {
+		if broken[i].Source != broken[j].Source {
+			return broken[i].Source < broken[j].Source
+		}
+
+		return broken[i].Target < broken[j].Target
+	})
+
+	return broken, nil
+}
+
+// PlanLinkFixes rewrites each broken link that has exactly one candidate
+func (v *Vault) PlanLinkFixes(broken []BrokenLink) (Plan, error) {
+	redirects := map[string]string{}
+
+	for _, link := range broken {
+		if fix, ok := link.Fix(); ok {
+			redirects[v.AbsPath(link.Target)] = v.AbsPath(fix)
+		}
+	}
+
+	if len(redirects) == 0 {
+		return Plan{}, nil
+	}
+
+	plan, err := v.planRedirects(redirects)
+	if err != nil {
+		return nil, fmt.Errorf("failed to plan link fixes: %w", err)
+	}
+
+	return plan, nil
+}
@@ -0,0 +1,429 @@
+package notes
+
+import (
+	_ "embed" // Required for compiler
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	//go:embed sql/selectIndexedNotesStmt.sql
+	selectIndexedNotesStmt string
+	//go:embed sql/selectEmbeddedFilenamesStmt.sql
+	selectEmbeddedFilenamesStmt string
+)
+
+// Severity ranks how much a finding of Doctor affects the vault
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+var severityRank = map[Severity]int{SeverityError: 0, SeverityWarning: 1, SeverityInfo: 2}
+
+// Fixes are applied in stages so that files are edited before they are renamed and renamed before reindexing
+const (
+	stageEdit = iota
+	stageRename
+	stageIndex
+)
+
+// Header keys that must contain a date or date-time
+var dateKeys = []string{KeyCreationDate, KeyStartDate, KeySoftDeadline, KeyHardDeadline}
+
+// Finding is a problem in the vault found by Doctor
+type Finding struct {
+	Severity Severity
+	// Path is the vault-relative reference of the file
+	Path    string
+	Message string
+	// Fix describes the automatic fix and is empty when the problem has to be fixed manually
+	Fix   string
+	fix   func() error
+	stage int
+}
+
+func (f Finding) String() string {
+	if f.Fix == "" {
+		return fmt.Sprintf("%s %s: %s", f.Severity, f.Path, f.Message)
+	}
+
+	return fmt.Sprintf("%s %s: %s (fix: %s)", f.Severity, f.Path, f.Message, f.Fix)
+}
+
+// Doctor audits the files in each subDir, the note headers, and the database index when it exists
+func (v *Vault) Doctor() ([]Finding, error) {
+	subDirs, err := v.ListSubDirs()
+	if err != nil {
+		return nil, err
+	}
+
+	findings := []Finding{}
+
+	for _, subDir := range subDirs {
+		subFindings, err := v.checkFiles(subDir)
+		if err != nil {
+			return nil, err
+		}
+
+		findings = append(findings, subFindings...)
+	}
+
+	stats, err := v.ListNotes("")
+	if err != nil {
+		return nil, err
+	}
+
+	headerFindings, err := v.checkHeaders(stats)
+	if err != nil {
+		return nil, err
+	}
+
+	duplicateFindings := v.checkDuplicateTimes(stats)
+
+	// A name used in two subDirs conflicts in the index, so rebuilding it would fail until the name is resolved
+	canReindex := true
+
+	for _, finding := range duplicateFindings {
+		canReindex = canReindex && finding.Severity != SeverityError
+	}
+
+	indexFindings, err := v.checkIndex(stats, canReindex)
+	if err != nil {
+		return nil, err
+	}
+
+	findings = append(findings, headerFindings...)
+	findings = append(findings, duplicateFindings...)
+	findings = append(findings, indexFindings...)
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if severityRank[findings[i].Severity] != severityRank[findings[j].Severity] {
+			return severityRank[findings[i].Severity] < severityRank[findings[j].Severity]
+		}
+
+		return findings[i].Path < findings[j].Path
+	})
+
+	return findings, nil
+}
+
+// FixFindings applies the automatic fixes and returns the findings that were fixed
+//
+// Findings can share a fix, such as rebuilding the index, which is only applied once
+func (v *Vault) FixFindings(findings []Finding) ([]Finding, error) {
+	fixed := []Finding{}
+	indexFixed := false
+
+	for _, stage := range []int{stageEdit, stageRename, stageIndex} {
+		for _, finding := range findings {
+			if finding.fix == nil || finding.stage != stage {
+				continue
+			}
+
+			if stage != stageIndex || !indexFixed {
+				if err := finding.fix(); err != nil {
+					return fixed, fmt.Errorf("failed to fix %s: %w", finding.Path, err)
+				}
+			}
+
+			indexFixed = indexFixed || stage == stageIndex
+			fixed = append(fixed, finding)
+		}
+	}
+
+	return fixed, nil
+}
+
+// Check the names of the files in a subDir, ignoring hidden files other than interrupted writes
+func (v *Vault) checkFiles(subDir string) ([]Finding, error) {
+	dir := filepath.Join(v.SyncDir, subDir)
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", dir, err)
+	}
+
+	findings := []Finding{}
+
+	for _, file := range files {
+		name := file.Name()
+		path := filepath.Join(dir, name)
+
+		switch {
+		case file.IsDir():
+		case IsNoteFile(name) && !IsTimeName(name):
+			findings = append(findings, Finding{
+				Severity: SeverityWarning,
+				Path:     v.RelPath(path),
+				Message:  "the name is not a creation timestamp",
+				Fix:      "rename by the creation time and redirect links",
+				fix:      func() error { return v.renameAndRedirect(path) },
+				stage:    stageRename,
+			})
+		case IsNoteFile(name):
+		case strings.HasPrefix(name, ".") && strings.Contains(name, NoteExt+".tmp"):
+			findings = append(findings, Finding{
+				Severity: SeverityWarning,
+				Path:     v.RelPath(path),
+				Message:  "temporary file left by an interrupted write",
+				Fix:      "delete",
+				fix:      func() error { return os.Remove(path) },
+				stage:    stageEdit,
+			})
+		case strings.HasPrefix(name, "."):
+		default:
+			findings = append(findings, Finding{
+				Severity: SeverityWarning,
+				Path:     v.RelPath(path),
+				Message:  fmt.Sprintf("not a %s note", NoteExt),
+			})
+		}
+	}
+
+	return findings, nil
+}
+
+// Rename a note by the creation time and redirect the links to it
+func (v *Vault) renameAndRedirect(path string) error {
+	toPath, _, err := v.RenameNote(path)
+	if err != nil {
+		return err
+	}
+
+	plan, err := v.planRedirects(map[string]string{path: toPath})
+	if err != nil {
+		return err
+	}
+
+	return v.Apply(plan)
+}
+
+// Remove trailing whitespace from the leading lines that are header lines without it
+func trimHeaderWhitespace(content string) string {
+	lines := strings.SplitAfter(content, "\n")
+
+	for i, line := range lines {
+		eol := line[len(strings.TrimRight(line, "\r\n")):]
+
+		trimmed := strings.TrimRight(line, " \t\r\n")
+		if !headerLineRe.MatchString(trimmed) {
+			break
+		}
+
+		lines[i] = trimmed + eol
+	}
+
+	return strings.Join(lines, "")
+}
+
+func (v *Vault) checkHeaders(stats []FileStat) ([]Finding, error) {
+	findings := []Finding{}
+
+	for _, stat := range stats {
+		content, err := os.ReadFile(stat.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %s: %w", stat.Path, err)
+		}
+
+		ref := v.RelPath(stat.Path)
+		header := ParseDocument(string(content)).Header
+		trimmed := trimHeaderWhitespace(string(content))
+		trimmedHeader := ParseDocument(trimmed).Header
+		lines := strings.Split(string(content), "\n")
+
+		switch {
+		case trimmedHeader.Len() > header.Len():
+			path := stat.Path
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Path:     ref,
+				Message:  fmt.Sprintf("header line %d has trailing whitespace after the '\\'", header.Len()+1),
+				Fix:      "remove the trailing whitespace",
+				fix:      func() error { return writeFileAtomic(path, []byte(trimmed)) },
+				stage:    stageEdit,
+			})
+
+			header = trimmedHeader
+		case header.Len() < len(lines) && strings.HasPrefix(lines[header.Len()], ": "):
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Path:     ref,
+				Message:  fmt.Sprintf("line %d is not a header line in the `: key=value\\` format", header.Len()+1),
+			})
+		}
+
+		for _, message := range headerErrors(header) {
+			findings = append(findings, Finding{Severity: SeverityError, Path: ref, Message: message})
+		}
+	}
+
+	return findings, nil
+}
+
+// The values in the header that can't be parsed
+func headerErrors(header Header) []string {
+	messages := []string{}
+
+	if _, err := header.State(); err != nil {
+		messages = append(messages, err.Error())
+	}
+
+	for _, key := range dateKeys {
+		if _, _, err := header.GetTime(key); err != nil {
+			messages = append(messages, err.Error())
+		}
+	}
+
+	if value, ok := header.Get(KeyRepeat); ok {
+		if _, err := ParseRepeat(value); err != nil {
+			messages = append(messages, err.Error())
+		}
+	}
+
+	return messages
+}
+
+// Find notes with the same creation time, which is an error when the names are the same because the index is
+// keyed by the name
+func (v *Vault) checkDuplicateTimes(stats []FileStat) []Finding {
+	byTime := map[time.Time][]FileStat{}
+
+	for _, stat := range stats {
+		if t, err := FromTimeName(strings.TrimSuffix(stat.Name, NoteExt)); err == nil {
+			byTime[t.UTC()] = append(byTime[t.UTC()], stat)
+		}
+	}
+
+	findings := []Finding{}
+
+	for _, group := range byTime {
+		if len(group) < 2 {
+			continue
+		}
+
+		names := map[string]int{}
+		for _, stat := range group {
+			names[stat.Name]++
+		}
+
+		for _, stat := range group {
+			others := []string{}
+
+			for _, other := range group {
+				if other.Path != stat.Path {
+					others = append(others, v.RelPath(other.Path))
+				}
+			}
+
+			sort.Strings(others)
+
+			finding := Finding{
+				Severity: SeverityInfo,
+				Path:     v.RelPath(stat.Path),
+				Message:  "the creation time is shared with " + strings.Join(others, ", "),
+			}
+
+			if names[stat.Name] > 1 {
+				finding.Severity = SeverityError
+				finding.Message = "the name is also used in another subDir, which conflicts in the index: " +
+					strings.Join(others, ", ")
+			}
+
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings
+}
+
+// Compare the database index to the notes, which is skipped when the index hasn't been created
+//
+// The stale findings only offer to rebuild the index when canReindex is set
+func (v *Vault) checkIndex(stats []FileStat, canReindex bool) ([]Finding, error) {
+	if v.MigrationsDir == "" || !exists(filepath.Join(v.SyncDir, dbFilename)) {
+		return []Finding{{Severity: SeverityInfo, Path: dbFilename, Message: "there is no database index to check"}}, nil
+	}
+
+	db, err := v.OpenIndex()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	var indexed []Note
+	if err := db.Select(&indexed, removeSQLFluffComments(selectIndexedNotesStmt)); err != nil {
+		return nil, fmt.Errorf("failed to select indexed notes: %w", err)
+	}
+
+	var embedded []string
+	if err := db.Select(&embedded, removeSQLFluffComments(selectEmbeddedFilenamesStmt)); err != nil {
+		return nil, fmt.Errorf("failed to select embeddings: %w", err)
+	}
+
+	reindex := func() error {
+		db, err := v.OpenIndex()
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		return v.Reindex(db)
+	}
+
+	stale := func(ref, message string) Finding {
+		if !canReindex {
+			return Finding{
+				Severity: SeverityWarning, Path: ref,
+				Message: message + ", but the index can't be rebuilt until the names used in two subDirs are resolved",
+			}
+		}
+
+		return Finding{
+			Severity: SeverityWarning, Path: ref, Message: message, Fix: "rebuild the index", fix: reindex, stage: stageIndex,
+		}
+	}
+
+	files := map[string]bool{}
+	names := map[string]bool{}
+
+	for _, stat := range stats {
+		files[v.RelPath(stat.Path)] = true
+		names[stat.Name] = true
+	}
+
+	findings := []Finding{}
+	indexedRefs := map[string]bool{}
+	indexedNames := map[string]bool{}
+
+	for _, note := range indexed {
+		ref := note.SubDir + "/" + note.Filename
+		indexedRefs[ref] = true
+		indexedNames[note.Filename] = true
+
+		if !files[ref] {
+			findings = append(findings, stale(ref, "the indexed note no longer exists"))
+		}
+	}
+
+	for _, name := range embedded {
+		if !indexedNames[name] && !names[name] {
+			findings = append(findings, stale(name, "the indexed embeddings belong to a note that no longer exists"))
+		}
+	}
+
+	for _, stat := range stats {
+		if ref := v.RelPath(stat.Path); !indexedRefs[ref] {
+			findings = append(findings, stale(ref, "the note is missing from the index"))
+		}
+	}
+
+	return findings, nil
+}
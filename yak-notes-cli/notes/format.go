@@ -0,0 +1,186 @@
+package notes
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/djot"
+)
+
+var headingMarkerRe = regexp.MustCompile(`^(#+)[ \t]+`)
+
+// FormatIssue is a difference from the canonical style on a line of the original note
+type FormatIssue struct {
+	Line    int
+	Message string
+}
+
+func (i FormatIssue) String() string {
+	return fmt.Sprintf("%d: %s", i.Line, i.Message)
+}
+
+// bodyLayout is what the canonical style needs to know about each line of the djot body
+type bodyLayout struct {
+	// verbatim lines are the content of code and raw blocks, which are never changed
+	verbatim map[int]bool
+	headings map[int]bool
+	// bullets are the columns of bullet list markers to replace with `-`
+	bullets map[int]int
+}
+
+func isBulletList(node *djot.Node) bool {
+	return node.Kind == djot.KindBulletList || node.Kind == djot.KindTaskList
+}
+
+// Collect the layout of the blocks, where a list next to another list keeps its marker so that they stay separate
+func (l *bodyLayout) collect(blocks []*djot.Node, topLevel bool) {
+	for i, node := range blocks {
+		switch node.Kind {
+		case djot.KindCodeBlock, djot.KindRawBlock:
+			for line := node.Start.Line + 1; line <= node.End.Line; line++ {
+				l.verbatim[line] = true
+			}
+		case djot.KindHeading:
+			if topLevel {
+				l.headings[node.Start.Line] = true
+			}
+		}
+
+		separate := i > 0 && isBulletList(blocks[i-1]) || i+1 < len(blocks) && isBulletList(blocks[i+1])
+		if isBulletList(node) && !separate {
+			for _, item := range node.Children {
+				l.bullets[item.Start.Line] = item.Start.Col
+			}
+		}
+
+		l.collect(node.Children, topLevel && node.Kind == djot.KindSection)
+	}
+}
+
+// FormatNote rewrites a note in the canonical djot style and lists the changes
+//
+// The header is followed by one blank line, bullets use `-`, headings have one space after the marker and a
+// blank line before, and there are no trailing whitespace or repeated blank lines outside of code blocks
+func FormatNote(content string) (string, []FormatIssue) {
+	eol := "\n"
+	if strings.Contains(content, "\r\n") {
+		eol = "\r\n"
+		content = strings.ReplaceAll(content, "\r\n", "\n")
+	}
+
+	issues := []FormatIssue{}
+	lines := strings.Split(content, "\n")
+
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	} else {
+		issues = append(issues, FormatIssue{Line: len(lines), Message: "missing newline at the end of the file"})
+	}
+
+	formatted := []string{}
+	headerLines := 0
+
+	for _, line := range lines {
+		trimmed := strings.TrimRight(line, " \t")
+		if !headerLineRe.MatchString(trimmed) {
+			break
+		}
+
+		headerLines++
+
+		if trimmed != line {
+			issues = append(issues, FormatIssue{Line: headerLines, Message: "trailing whitespace"})
+		}
+
+		formatted = append(formatted, trimmed)
+	}
+
+	body := lines[headerLines:]
+	doc := djot.Parse(strings.Join(body, "\n") + "\n")
+	layout := bodyLayout{verbatim: map[int]bool{}, headings: map[int]bool{}, bullets: map[int]int{}}
+	layout.collect(doc.Children, true)
+
+	// pending is set when the next line follows a blank line
+	pending := false
+
+	for i, line := range body {
+		number := headerLines + i + 1
+		if layout.verbatim[i+1] {
+			formatted = append(formatted, line)
+			continue
+		}
+
+		trimmed := strings.TrimRight(line, " \t")
+		if trimmed != line {
+			issues = append(issues, FormatIssue{Line: number, Message: "trailing whitespace"})
+		}
+
+		if trimmed == "" {
+			switch {
+			case len(formatted) == 0:
+				issues = append(issues, FormatIssue{Line: number, Message: "blank line at the start of the file"})
+			case pending:
+				issues = append(issues, FormatIssue{Line: number, Message: "extra blank line"})
+			}
+
+			pending = len(formatted) > 0
+
+			continue
+		}
+
+		switch {
+		case pending:
+		case i == 0 && headerLines > 0:
+			issues = append(issues, FormatIssue{Line: number, Message: "missing blank line after the header"})
+			pending = true
+		case layout.headings[i+1] && len(formatted) > 0:
+			issues = append(issues, FormatIssue{Line: number, Message: "missing blank line before the heading"})
+			pending = true
+		}
+
+		if pending {
+			formatted = append(formatted, "")
+			pending = false
+		}
+
+		if m := headingMarkerRe.FindStringSubmatch(trimmed); layout.headings[i+1] && m != nil && m[0] != m[1]+" " {
+			issues = append(issues, FormatIssue{Line: number, Message: "use one space after the heading marker"})
+			trimmed = m[1] + " " + trimmed[len(m[0]):]
+		}
+
+		if col, ok := layout.bullets[i+1]; ok && col <= len(trimmed) && trimmed[col-1] != '-' {
+			message := fmt.Sprintf("use '-' instead of '%c' for the bullet", trimmed[col-1])
+			issues = append(issues, FormatIssue{Line: number, Message: message})
+			trimmed = trimmed[:col-1] + "-" + trimmed[col:]
+		}
+
+		formatted = append(formatted, trimmed)
+	}
+
+	if pending {
+		issues = append(issues, FormatIssue{Line: len(lines), Message: "blank lines at the end of the file"})
+	}
+
+	if len(formatted) == 0 {
+		return "", issues
+	}
+
+	return strings.Join(formatted, eol) + eol, issues
+}
+
+// FormatFile lists the issues of the note and rewrites it in the canonical style unless check is set
+func FormatFile(path string, check bool) ([]FormatIssue, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", path, err)
+	}
+
+	formatted, issues := FormatNote(string(content))
+	if check || formatted == string(content) {
+		return issues, nil
+	}
+
+	return issues, writeFileAtomic(path, []byte(formatted))
+}
@@ -0,0 +1,362 @@
+package notes
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Header keys with a shared meaning across commands
+const (
+	KeyCreationDate = "creation_date"
+	KeyHardDeadline = "hard-deadline"
+	KeyLinks        = "links"
+	KeyMergedFrom   = "merged-from"
+	KeyName         = "name"
+	KeySoftDeadline = "soft-deadline"
+	KeySplitFrom    = "split-from"
+	KeyStartDate    = "start-date"
+	KeyState        = "state"
+)
+
+const listSep = ","
+
+var (
+	ErrInvalidState = errors.New("invalid state")
+
+	headerLineRe = regexp.MustCompile(`^: ([^=\s]+)=(.*)\\$`)
+)
+
+// State is the review or task state of a note. Notes without a state are unreviewed
+type State string
+
+const (
+	StateNone       State = ""
+	StateAtomic     State = "Atomic"
+	StateBacklog    State = "backlog"
+	StateQueue      State = "queue"
+	StateInProgress State = "in-progress"
+	StateComplete   State = "complete"
+	StateNotPlanned State = "not-planned"
+)
+
+// States lists every valid state that can be set on a note
+var States = []State{
+	StateAtomic, StateBacklog, StateQueue, StateInProgress, StateComplete, StateNotPlanned,
+}
+
+func ParseState(value string) (State, error) {
+	state := State(value)
+	if state != StateNone && !slices.Contains(States, state) {
+		return StateNone, fmt.Errorf("%w: '%s' is not one of %v", ErrInvalidState, value, States)
+	}
+
+	return state, nil
+}
+
+type headerField struct {
+	key, value string
+}
+
+// Header is the ordered `: key=value\` metadata at the top of a note
+//
+// Unknown keys are preserved so that the header can be written back unchanged
+type Header struct {
+	fields []headerField
+	// eol is the line ending of the parsed header, which defaults to "\n"
+	eol string
+}
+
+func (h *Header) Len() int {
+	return len(h.fields)
+}
+
+func (h *Header) Keys() []string {
+	keys := make([]string, 0, len(h.fields))
+	for _, f := range h.fields {
+		keys = append(keys, f.key)
+	}
+
+	return keys
+}
+
+func (h *Header) Get(key string) (string, bool) {
+	for _, f := range h.fields {
+		if f.key == key {
+			return f.value, true
+		}
+	}
+
+	return "", false
+}
+
+// Set updates the value in place or appends the key when new
+func (h *Header) Set(key, value string) {
+	value = strings.ReplaceAll(strings.ReplaceAll(value, "\r", " "), "\n", " ")
+
+	for i, f := range h.fields {
+		if f.key == key {
+			h.fields[i].value = value
+			return
+		}
+	}
+
+	h.fields = append(h.fields, headerField{key: key, value: value})
+}
+
+func (h *Header) Delete(key string) {
+	h.fields = slices.DeleteFunc(h.fields, func(f headerField) bool { return f.key == key })
+}
+
+func (h *Header) GetList(key string) []string {
+	value, _ := h.Get(key)
+
+	var items []string
+
+	for item := range strings.SplitSeq(value, listSep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// SetList stores the items or removes the key when there are none
+func (h *Header) SetList(key string, items []string) {
+	if len(items) == 0 {
+		h.Delete(key)
+		return
+	}
+
+	h.Set(key, strings.Join(items, listSep))
+}
+
+// AppendList adds each item that is not already in the list
+func (h *Header) AppendList(key string, items ...string) {
+	list := h.GetList(key)
+	for _, item := range items {
+		if !slices.Contains(list, item) {
+			list = append(list, item)
+		}
+	}
+
+	h.SetList(key, list)
+}
+
+// GetTime parses a date or date-time value. The boolean is false when the key is missing
+func (h *Header) GetTime(key string) (time.Time, bool, error) {
+	value, ok := h.Get(key)
+	if !ok || value == "" {
+		return time.Time{}, false, nil
+	}
+
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", time.DateOnly} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true, nil
+		}
+	}
+
+	return time.Time{}, true, fmt.Errorf("failed to parse '%s' as a date for '%s'", value, key)
+}
+
+func (h *Header) SetTime(key string, t time.Time) {
+	h.Set(key, t.Format(time.RFC3339))
+}
+
+func (h *Header) State() (State, error) {
+	value, _ := h.Get(KeyState)
+	return ParseState(value)
+}
+
+func (h *Header) SetState(state State) {
+	if state == StateNone {
+		h.Delete(KeyState)
+		return
+	}
+
+	h.Set(KeyState, string(state))
+}
+
+// Schedule is the planning metadata of a task. Unset dates are zero
+type Schedule struct {
+	Start        time.Time
+	SoftDeadline time.Time
+	HardDeadline time.Time
+}
+
+// Deadline is the earliest deadline that is set
+func (s Schedule) Deadline() time.Time {
+	switch {
+	case s.SoftDeadline.IsZero():
+		return s.HardDeadline
+	case s.HardDeadline.IsZero() || s.SoftDeadline.Before(s.HardDeadline):
+		return s.SoftDeadline
+	default:
+		return s.HardDeadline
+	}
+}
+
+func (s Schedule) IsZero() bool {
+	return s.Start.IsZero() && s.SoftDeadline.IsZero() && s.HardDeadline.IsZero()
+}
+
+func (h *Header) Schedule() (s Schedule, err error) {
+	for key, dest := range map[string]*time.Time{
+		KeyStartDate: &s.Start, KeySoftDeadline: &s.SoftDeadline, KeyHardDeadline: &s.HardDeadline,
+	} {
+		if *dest, _, err = h.GetTime(key); err != nil {
+			return Schedule{}, err
+		}
+	}
+
+	return s, nil
+}
+
+func (h *Header) String() string {
+	eol := h.eol
+	if eol == "" {
+		eol = "\n"
+	}
+
+	var b strings.Builder
+	for _, f := range h.fields {
+		fmt.Fprintf(&b, ": %s=%s\\%s", f.key, f.value, eol)
+	}
+
+	return b.String()
+}
+
+// Document is a note split into the Header and the untouched body
+type Document struct {
+	Header Header
+	Body   string
+	// sep is the blank line that separated the parsed header from the body
+	sep string
+	// compact is set when the body directly followed the parsed header
+	compact bool
+}
+
+func ParseDocument(content string) Document {
+	doc := Document{}
+	rest := content
+
+	for rest != "" {
+		line, after, found := strings.Cut(rest, "\n")
+
+		matches := headerLineRe.FindStringSubmatch(strings.TrimSuffix(line, "\r"))
+		if matches == nil {
+			break
+		}
+
+		if doc.Header.eol == "" && strings.HasSuffix(line, "\r") {
+			doc.Header.eol = "\r\n"
+		}
+
+		doc.Header.fields = append(doc.Header.fields, headerField{key: matches[1], value: matches[2]})
+
+		if !found {
+			rest = ""
+			break
+		}
+
+		rest = after
+	}
+
+	if doc.Header.Len() > 0 && rest != "" {
+		line, after, found := strings.Cut(rest, "\n")
+		if found && strings.TrimSpace(line) == "" {
+			doc.sep = line + "\n"
+			rest = after
+		} else {
+			doc.compact = true
+		}
+	}
+
+	doc.Body = rest
+
+	return doc
+}
+
+func (d *Document) String() string {
+	switch {
+	case d.Header.Len() == 0:
+		return d.Body
+	case d.compact:
+		return d.Header.String() + d.Body
+	case d.sep != "":
+		return d.Header.String() + d.sep + d.Body
+	case d.Body == "":
+		return d.Header.String()
+	default:
+		return d.Header.String() + "\n" + d.Body
+	}
+}
+
+func ReadDocument(path string) (Document, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Document{}, fmt.Errorf("failed to read file %s: %w", path, err)
+	}
+
+	return ParseDocument(string(content)), nil
+}
+
+// WriteDocument atomically replaces the file at path
+func WriteDocument(path string, doc Document) error {
+	return writeFileAtomic(path, []byte(doc.String()))
+}
+
+// Write to a temporary file in the same directory as path and return the temporary path
+func writeTempFile(path string, data []byte, mode os.FileMode) (_ string, err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file for %s: %w", path, err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write %s: %w", tmp.Name(), err)
+	}
+
+	if err = tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to close %s: %w", tmp.Name(), err)
+	}
+
+	if err = os.Chmod(tmp.Name(), mode); err != nil {
+		return "", fmt.Errorf("failed to set permissions on %s: %w", tmp.Name(), err)
+	}
+
+	return tmp.Name(), nil
+}
+
+// Write to a temporary file in the same directory, then rename over the destination
+func writeFileAtomic(path string, data []byte) error {
+	mode := os.FileMode(0o644)
+	if fi, statErr := os.Stat(path); statErr == nil {
+		mode = fi.Mode().Perm()
+	}
+
+	tmp, err := writeTempFile(path, data, mode)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+
+	return nil
+}
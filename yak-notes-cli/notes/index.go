@@ -0,0 +1,420 @@
+package notes
+
+import (
+	_ "embed" // Required for compiler
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/marcboeker/go-duckdb" // Configure DuckDB driver
+
+	"github.com/KyleKing/yak-shears/geese-migrations/library"
+)
+
+// TODO: implement Ollama client for embeddings
+//  https://gobyexample.com/http-client
+//  https://www.digitalocean.com/community/tutorials/how-to-make-http-requests-in-go
+
+var (
+	//go:embed sql/insertNotesStmt.sql
+	insertNotesStmt string
+	//go:embed sql/insertEmbeddingsStmt.sql
+	insertEmbeddingsStmt string
+	//go:embed sql/insertLinksStmt.sql
+	insertLinksStmt string
+	//go:embed sql/selectBacklinksStmt.sql
+	selectBacklinksStmt string
+	//go:embed sql/searchQueryStmt.sql
+	searchQueryStmt string
+	//go:embed sql/updateNoteSubDirStmt.sql
+	updateNoteSubDirStmt string
+	//go:embed sql/updateLinkRefsStmt.sql
+	updateLinkRefsStmt string
+	//go:embed sql/deleteEmbeddingsStmt.sql
+	deleteEmbeddingsStmt string
+	//go:embed sql/deleteNoteStmt.sql
+	deleteNoteStmt string
+	//go:embed sql/deleteLinksFromStmt.sql
+	deleteLinksFromStmt string
+)
+
+var ErrNoMigrationsDir = errors.New("no migrations directory is configured for the vault")
+
+const dbFilename = "yak-shears.db"
+
+// Remove SQLFluff comments, which include colons and cause issues
+func removeSQLFluffComments(sql string) string {
+	re := regexp.MustCompile(`(?m)^-- .+$`)
+	sql = re.ReplaceAllString(sql, "")
+
+	return strings.TrimSpace(sql)
+}
+
+// Batch insert modified notes
+func storeNotes(db *sqlx.DB, notes []Note, chunkingFunc func(string) []string) (err error) {
+	if len(notes) == 0 {
+		return nil
+	}
+
+	if _, err = db.NamedExec(removeSQLFluffComments(insertNotesStmt), notes); err != nil {
+		return fmt.Errorf("failed to execute batch insertNotes: %w", err)
+	}
+
+	// Prepare embeddings for batch insert
+	var embeddings []map[string]interface{}
+
+	for _, note := range notes {
+		chunks := chunkingFunc(note.Content)
+		for _, chunk := range chunks {
+			if len(chunk) > 0 {
+				embeddings = append(embeddings, map[string]interface{}{
+					"filename":  note.Filename,
+					"embedding": chunk,
+				})
+			}
+		}
+	}
+
+	// Batch insert embeddings
+	if len(embeddings) > 0 {
+		if _, err = db.NamedExec(removeSQLFluffComments(insertEmbeddingsStmt), embeddings); err != nil {
+			return fmt.Errorf("failed to execute batch insertEmbeddings: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Batch insert the outbound links of each note
+func (v *Vault) storeLinks(db *sqlx.DB, notes []Note) error {
+	var links []map[string]interface{}
+
+	for _, note := range notes {
+		path := filepath.Join(v.SyncDir, note.SubDir, note.Filename)
+		for _, target := range v.NoteLinks(path, ParseDocument(note.Content)) {
+			links = append(links, map[string]interface{}{
+				"source": v.RelPath(path),
+				"target": target,
+			})
+		}
+	}
+
+	if len(links) > 0 {
+		if _, err := db.NamedExec(removeSQLFluffComments(insertLinksStmt), links); err != nil {
+			return fmt.Errorf("failed to execute batch insertLinks: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Default chunking logic: split by paragraph, then by sentence if necessary
+func defaultChunkingLogic(content string) []string {
+	var chunks []string
+
+	paragraphs := strings.SplitSeq(content, "\n\n")
+	for paragraph := range paragraphs {
+		if len(paragraph) > 500 { // Example threshold for large chunks
+			sentences := strings.Split(paragraph, ". ")
+			chunks = append(chunks, sentences...)
+		} else {
+			chunks = append(chunks, paragraph)
+		}
+	}
+
+	return chunks
+}
+
+// Read the row of the database index for a note
+func readNote(stat FileStat) (Note, error) {
+	content, err := os.ReadFile(stat.Path)
+	if err != nil {
+		return Note{}, fmt.Errorf("failed to read file %s: %w", stat.Name, err)
+	}
+
+	doc := ParseDocument(string(content))
+
+	return Note{
+		SubDir:     stat.SubDir,
+		Filename:   stat.Name,
+		Title:      doc.Title(),
+		Content:    string(content),
+		ModifiedAt: stat.ModTime.Format(time.RFC3339),
+	}, nil
+}
+
+func (v *Vault) ingestSubdir(db *sqlx.DB, subDir string) (err error) {
+	stats, err := v.ListNotes(subDir)
+	if err != nil {
+		return err
+	}
+
+	notes := []Note{}
+
+	for _, stat := range stats {
+		note, err := readNote(stat)
+		if err != nil {
+			return err
+		}
+
+		notes = append(notes, note)
+	}
+
+	if err := storeNotes(db, notes, defaultChunkingLogic); err != nil {
+		return fmt.Errorf("failed to store notes for subdir %s: %w", subDir, err)
+	}
+
+	if err := v.storeLinks(db, notes); err != nil {
+		return fmt.Errorf("failed to store links for subdir %s: %w", subDir, err)
+	}
+
+	return nil
+}
+
+// Purge data
+func purgeData(db *sqlx.DB) (err error) {
+	_, err = db.Exec("DELETE FROM link")
+	if err != nil {
+		return fmt.Errorf("failed to purge link table: %w", err)
+	}
+
+	_, err = db.Exec("DELETE FROM embedding")
+	if err != nil {
+		return fmt.Errorf("failed to purge embedding table: %w", err)
+	}
+
+	_, err = db.Exec("DELETE FROM note")
+	if err != nil {
+		return fmt.Errorf("failed to purge note table: %w", err)
+	}
+
+	return nil
+}
+
+// Ingest ALL Notes
+func (v *Vault) ingestAllNotes(db *sqlx.DB) (err error) {
+	folderNames, err := v.ListSubDirs()
+	if err != nil {
+		return fmt.Errorf("failed to list subdirectories in %s: %w", v.SyncDir, err)
+	}
+
+	for _, subDir := range folderNames {
+		err := v.ingestSubdir(db, subDir)
+		if err != nil {
+			return fmt.Errorf("failed to ingest subdir %s: %w", subDir, err)
+		}
+	}
+
+	return nil
+}
+
+// OpenIndex applies any pending migrations and connects to the database index
+func (v *Vault) OpenIndex() (db *sqlx.DB, err error) {
+	if v.MigrationsDir == "" {
+		return nil, ErrNoMigrationsDir
+	}
+
+	dsn := filepath.Join(v.SyncDir, dbFilename+"?access_mode=READ_WRITE")
+
+	err = library.AutoUpgrade("root", v.MigrationsDir, "duckdb", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("processMigrations failed: %w", err)
+	}
+
+	db, err = sqlx.Open("duckdb", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database at %s: %w", dsn, err)
+	}
+
+	return db, nil
+}
+
+// Reindex replaces the database index with the current content of the vault
+func (v *Vault) Reindex(db *sqlx.DB) error {
+	// HACK: replace with incremental ingestion
+	if err := purgeData(db); err != nil {
+		return err
+	}
+
+	return v.ingestAllNotes(db)
+}
+
+// Search returns up to limit notes that contain the query, with title matches first and then the most recently modified
+func (v *Vault) Search(query string, limit int) (notes []Note, err error) {
+	db, err := v.OpenIndex()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	if err = v.Reindex(db); err != nil {
+		return nil, err
+	}
+
+	return SearchIndex(db, query, limit)
+}
+
+// SearchIndex is Search without reindexing, for callers that keep track of when the index is stale
+func SearchIndex(db *sqlx.DB, query string, limit int) (notes []Note, err error) {
+	nstmt, err := db.PrepareNamed(removeSQLFluffComments(searchQueryStmt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare search query: %w", err)
+	}
+	defer nstmt.Close()
+
+	err = nstmt.Select(&notes, map[string]interface{}{
+		"query":   query,
+		"limit_":  limit,
+		"offset_": 0,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute search query: %w", err)
+	}
+
+	return notes, nil
+}
+
+// Fingerprint identifies the notes and their modification times, so it changes when a note is added, removed, or
+// edited and the index is stale
+func (v *Vault) Fingerprint() (string, error) {
+	stats, err := v.ListNotes("")
+	if err != nil {
+		return "", err
+	}
+
+	hash := fnv.New64a()
+	for _, stat := range stats {
+		fmt.Fprintf(hash, "%s\x00%d\x00", stat.Path, stat.ModTime.UnixNano())
+	}
+
+	return strconv.FormatUint(hash.Sum64(), 16), nil
+}
+
+// Backlinks returns the vault-relative references of every note that links to the note at path
+func (v *Vault) Backlinks(path string) (sources []string, err error) {
+	db, err := v.OpenIndex()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	if err = v.Reindex(db); err != nil {
+		return nil, err
+	}
+
+	nstmt, err := db.PrepareNamed(removeSQLFluffComments(selectBacklinksStmt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare backlinks query: %w", err)
+	}
+	defer nstmt.Close()
+
+	err = nstmt.Select(&sources, map[string]interface{}{"target": v.RelPath(path)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute backlinks query: %w", err)
+	}
+
+	return sources, nil
+}
+
+// MoveIndexed updates the subDir and the link references of a moved note when the database index exists
+func (v *Vault) MoveIndexed(fromPath, toPath string) error {
+	if v.MigrationsDir == "" || !exists(filepath.Join(v.SyncDir, dbFilename)) {
+		return nil
+	}
+
+	db, err := v.OpenIndex()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	_, err = db.NamedExec(removeSQLFluffComments(updateNoteSubDirStmt), map[string]interface{}{
+		"sub_dir":  filepath.Base(filepath.Dir(toPath)),
+		"filename": filepath.Base(toPath),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update the subDir of %s: %w", filepath.Base(toPath), err)
+	}
+
+	_, err = db.NamedExec(removeSQLFluffComments(updateLinkRefsStmt), map[string]interface{}{
+		"from_ref": v.RelPath(fromPath),
+		"to_ref":   v.RelPath(toPath),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update links to %s: %w", v.RelPath(toPath), err)
+	}
+
+	return nil
+}
+
+// Delete the rows of a note and the links from it
+func deleteIndexed(db *sqlx.DB, filename, ref string) error {
+	for _, stmt := range []string{deleteEmbeddingsStmt, deleteNoteStmt} {
+		if _, err := db.NamedExec(removeSQLFluffComments(stmt), map[string]interface{}{"filename": filename}); err != nil {
+			return fmt.Errorf("failed to delete %s from the index: %w", ref, err)
+		}
+	}
+
+	if _, err := db.NamedExec(removeSQLFluffComments(deleteLinksFromStmt), map[string]interface{}{"source": ref}); err != nil {
+		return fmt.Errorf("failed to delete links from %s: %w", ref, err)
+	}
+
+	return nil
+}
+
+// RemoveIndexed deletes a note that was moved out of its subDir from the database index when it exists
+//
+// The links to the note are kept, so that they are found again when the note is restored
+func (v *Vault) RemoveIndexed(fromPath string) error {
+	if v.MigrationsDir == "" || !exists(filepath.Join(v.SyncDir, dbFilename)) {
+		return nil
+	}
+
+	db, err := v.OpenIndex()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return deleteIndexed(db, filepath.Base(fromPath), v.RelPath(fromPath))
+}
+
+// AddIndexed adds or replaces a note in the database index when it exists
+func (v *Vault) AddIndexed(path string) error {
+	if v.MigrationsDir == "" || !exists(filepath.Join(v.SyncDir, dbFilename)) {
+		return nil
+	}
+
+	stat, err := v.statNote(path)
+	if err != nil {
+		return err
+	}
+
+	note, err := readNote(stat)
+	if err != nil {
+		return err
+	}
+
+	db, err := v.OpenIndex()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := deleteIndexed(db, stat.Name, v.RelPath(path)); err != nil {
+		return err
+	}
+
+	if err := storeNotes(db, []Note{note}, defaultChunkingLogic); err != nil {
+		return fmt.Errorf("failed to index %s: %w", v.RelPath(path), err)
+	}
+
+	return v.storeLinks(db, []Note{note})
+}
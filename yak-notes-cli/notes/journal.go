@@ -0,0 +1,119 @@
+package notes
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Header keys for daily journal notes
+const (
+	KeyJournalDate     = "journal-date"
+	KeyPreviousJournal = "previous-journal"
+)
+
+// Heading for the unfinished tasks copied from the previous journal
+const carriedOverHeading = "## Carried over"
+
+// Journal is a note that records the daily log for Date
+type Journal struct {
+	Stat FileStat
+	Date time.Time
+	Doc  Document
+}
+
+// List the journal notes in the subDir by the date in the header
+func (v *Vault) listJournals(subDir string) ([]Journal, error) {
+	stats, err := v.ListNotes(subDir)
+	if err != nil {
+		return nil, err
+	}
+
+	journals := []Journal{}
+
+	for _, stat := range stats {
+		doc, err := ReadDocument(stat.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		date, ok, err := doc.Header.GetTime(KeyJournalDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid journal date in %s: %w", v.RelPath(stat.Path), err)
+		} else if ok {
+			journals = append(journals, Journal{Stat: stat, Date: calendarDate(date), Doc: doc})
+		}
+	}
+
+	return journals, nil
+}
+
+// The unchecked task list items of a note
+func unfinishedTasks(body string) []string {
+	tasks := []string{}
+
+	for _, item := range ParseTasks(body) {
+		if !item.Done {
+			tasks = append(tasks, "- [ ] "+item.Text)
+		}
+	}
+
+	return tasks
+}
+
+// OpenJournal returns the journal note for the date in the subDir, which is created when missing
+//
+// New journals are rendered from the subDir template, link to the previous journal, and carry over unfinished tasks
+func (v *Vault) OpenJournal(subDir string, date time.Time) (path string, created bool, err error) {
+	if err := v.checkSubDir(subDir); err != nil {
+		return "", false, err
+	}
+
+	journals, err := v.listJournals(subDir)
+	if err != nil {
+		return "", false, err
+	}
+
+	day := calendarDate(date)
+
+	var previous *Journal
+
+	for i, journal := range journals {
+		switch {
+		case journal.Date.Equal(day):
+			return journal.Stat.Path, false, nil
+		case journal.Date.Before(day) && (previous == nil || journal.Date.After(previous.Date)):
+			previous = &journals[i]
+		}
+	}
+
+	now := time.Now()
+	content := ""
+
+	if name := v.SubDirTemplate(subDir); name != "" {
+		data := NewTemplateData(now, subDir, day.Format(time.DateOnly))
+		data.Date = day.Format(time.DateOnly)
+
+		if content, err = v.RenderTemplate(name, data); err != nil {
+			return "", false, err
+		}
+	}
+
+	doc := ParseDocument(content)
+	doc.Header.Set(KeyJournalDate, day.Format(time.DateOnly))
+
+	if previous != nil {
+		doc.Header.Set(KeyPreviousJournal, v.RelPath(previous.Stat.Path))
+
+		if tasks := unfinishedTasks(previous.Doc.Body); len(tasks) > 0 {
+			doc.Body = appendBody(doc.Body, carriedOverHeading+"\n\n"+strings.Join(tasks, "\n")+"\n")
+		}
+	}
+
+	path, err = v.CreateNoteWithContent(subDir, now, doc.String())
+	if err != nil {
+		return "", false, err
+	}
+
+	return path, true, nil
+}
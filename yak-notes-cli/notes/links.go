@@ -0,0 +1,315 @@
+package notes
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+var (
+	inlineLinkRe = regexp.MustCompile(`!?\[((?:\\.|[^\]\\])*)\]\(([^()\s]+)\)`)
+	autoLinkRe   = regexp.MustCompile(`<([^<>\s]+)>`)
+	refDefRe     = regexp.MustCompile(`(?m)^\[([^\]]+)\]:[ \t]*(\S+)`)
+	fenceRe      = regexp.MustCompile("(?m)^[ \t]*(`{3,}|~{3,})")
+)
+
+// Link is a destination parsed from djot link syntax with the byte offsets of the destination
+type Link struct {
+	Text  string
+	Dest  string
+	Start int
+	End   int
+}
+
+// Find the byte ranges of fenced code blocks, which can't contain links
+func codeBlockRanges(content string) [][2]int {
+	var ranges [][2]int
+
+	fences := fenceRe.FindAllStringSubmatchIndex(content, -1)
+	for i := 0; i < len(fences); i++ {
+		open := content[fences[i][2]:fences[i][3]]
+		closed := false
+
+		for j := i + 1; j < len(fences); j++ {
+			fence := content[fences[j][2]:fences[j][3]]
+			if fence[0] == open[0] && len(fence) >= len(open) {
+				ranges = append(ranges, [2]int{fences[i][0], fences[j][1]})
+				i = j
+				closed = true
+
+				break
+			}
+		}
+
+		if !closed {
+			ranges = append(ranges, [2]int{fences[i][0], len(content)})
+			break
+		}
+	}
+
+	return ranges
+}
+
+func inRanges(ranges [][2]int, idx int) bool {
+	for _, r := range ranges {
+		if idx >= r[0] && idx < r[1] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseLinks returns every inline link, autolink, and reference definition outside of code blocks
+func ParseLinks(content string) []Link {
+	code := codeBlockRanges(content)
+	links := []Link{}
+
+	for _, re := range []*regexp.Regexp{inlineLinkRe, refDefRe} {
+		for _, m := range re.FindAllStringSubmatchIndex(content, -1) {
+			if !inRanges(code, m[0]) {
+				links = append(links, Link{
+					Text: content[m[2]:m[3]], Dest: content[m[4]:m[5]], Start: m[4], End: m[5],
+				})
+			}
+		}
+	}
+
+	for _, m := range autoLinkRe.FindAllStringSubmatchIndex(content, -1) {
+		if !inRanges(code, m[0]) {
+			dest := content[m[2]:m[3]]
+			links = append(links, Link{Text: dest, Dest: dest, Start: m[2], End: m[3]})
+		}
+	}
+
+	sort.Slice(links, func(i, j int) bool { return links[i].Start < links[j].Start })
+
+	return links
+}
+
+func isExternalDest(dest string) bool {
+	return strings.Contains(dest, "://") || strings.HasPrefix(dest, "mailto:") || strings.HasPrefix(dest, "#")
+}
+
+// Split a destination into the path and the optional `#anchor`
+func splitAnchor(dest string) (string, string) {
+	if idx := strings.Index(dest, "#"); idx >= 0 {
+		return dest[:idx], dest[idx:]
+	}
+
+	return dest, ""
+}
+
+// LinkTarget resolves a link destination from the note at fromPath to an absolute note path
+func LinkTarget(fromPath, dest string) (string, bool) {
+	if isExternalDest(dest) {
+		return "", false
+	}
+
+	target, _ := splitAnchor(dest)
+	if !IsNoteFile(target) {
+		return "", false
+	}
+
+	if filepath.IsAbs(target) {
+		return filepath.Clean(target), true
+	}
+
+	return filepath.Join(filepath.Dir(fromPath), filepath.FromSlash(target)), true
+}
+
+// LinkDest is the relative destination to use in the note at fromPath to link to toPath
+func LinkDest(fromPath, toPath string) string {
+	rel, err := filepath.Rel(filepath.Dir(fromPath), toPath)
+	if err != nil {
+		return filepath.ToSlash(toPath)
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// Replace the destination of each note link where newDest returns a value
+func rewriteDests(fromPath, content string, newDest func(target string) (string, bool)) (string, int) {
+	var b strings.Builder
+
+	last, count := 0, 0
+
+	for _, link := range ParseLinks(content) {
+		target, ok := LinkTarget(fromPath, link.Dest)
+		if !ok || link.Start < last {
+			continue
+		}
+
+		dest, ok := newDest(target)
+		if !ok {
+			continue
+		}
+
+		_, anchor := splitAnchor(link.Dest)
+		b.WriteString(content[last:link.Start])
+		b.WriteString(dest + anchor)
+		last = link.End
+		count++
+	}
+
+	b.WriteString(content[last:])
+
+	return b.String(), count
+}
+
+// RewriteLinks replaces the destination of every note link where replace returns a new absolute path
+//
+// The anchor of the original destination is kept. The count of rewritten links is returned
+func RewriteLinks(fromPath, content string, replace func(target string) (string, bool)) (string, int) {
+	return rewriteDests(fromPath, content, func(target string) (string, bool) {
+		newTarget, ok := replace(target)
+		if !ok {
+			return "", false
+		}
+
+		return LinkDest(fromPath, newTarget), true
+	})
+}
+
+// StripLinks replaces every note link where remove returns true with the link text and deletes the reference
+// definitions, such as for a note that was deleted. The count of removed links is returned
+func StripLinks(fromPath, content string, remove func(target string) bool) (string, int) {
+	type span struct {
+		start, end int
+		text       string
+	}
+
+	code := codeBlockRanges(content)
+	spans := []span{}
+	matches := func(m []int) bool {
+		if inRanges(code, m[0]) {
+			return false
+		}
+
+		target, ok := LinkTarget(fromPath, content[m[4]:m[5]])
+
+		return ok && remove(target)
+	}
+
+	for _, m := range inlineLinkRe.FindAllStringSubmatchIndex(content, -1) {
+		if matches(m) {
+			spans = append(spans, span{start: m[0], end: m[1], text: content[m[2]:m[3]]})
+		}
+	}
+
+	for _, m := range refDefRe.FindAllStringSubmatchIndex(content, -1) {
+		if matches(m) {
+			end := len(content)
+			if idx := strings.IndexByte(content[m[1]:], '\n'); idx >= 0 {
+				end = m[1] + idx + 1
+			}
+
+			spans = append(spans, span{start: m[0], end: end})
+		}
+	}
+
+	for _, m := range autoLinkRe.FindAllStringSubmatchIndex(content, -1) {
+		if matches([]int{m[0], m[1], m[2], m[3], m[2], m[3]}) {
+			spans = append(spans, span{start: m[0], end: m[1], text: content[m[2]:m[3]]})
+		}
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	var b strings.Builder
+
+	last, count := 0, 0
+
+	for _, s := range spans {
+		if s.start < last {
+			continue
+		}
+
+		b.WriteString(content[last:s.start])
+		b.WriteString(s.text)
+		last = s.end
+		count++
+	}
+
+	b.WriteString(content[last:])
+
+	return b.String(), count
+}
+
+// RelocateLinks rewrites note links in content written for fromPath so that they resolve from toPath
+func RelocateLinks(fromPath, toPath, content string) string {
+	if filepath.Dir(fromPath) == filepath.Dir(toPath) {
+		return content
+	}
+
+	relocated, _ := rewriteDests(fromPath, content, func(target string) (string, bool) {
+		return LinkDest(toPath, target), true
+	})
+
+	return relocated
+}
+
+// Headers that contain vault-relative references to existing notes
+var referenceKeys = []string{KeyLinks, KeyPreviousJournal, KeyRepeatedFrom}
+
+// NoteLinks returns the vault-relative references of every note linked from the header and the body
+func (v *Vault) NoteLinks(path string, doc Document) []string {
+	refs := []string{}
+
+	for _, key := range referenceKeys {
+		refs = append(refs, doc.Header.GetList(key)...)
+	}
+
+	for _, link := range ParseLinks(doc.Body) {
+		if target, ok := LinkTarget(path, link.Dest); ok {
+			refs = append(refs, v.RelPath(target))
+		}
+	}
+
+	return refs
+}
+
+// AddLink records toPath in the header `links` of fromPath. Returns false when already linked
+func (v *Vault) AddLink(fromPath, toPath string) (bool, error) {
+	if fromPath == toPath {
+		return false, fmt.Errorf("%w: %s", ErrSameNote, v.RelPath(fromPath))
+	}
+
+	doc, err := ReadDocument(fromPath)
+	if err != nil {
+		return false, err
+	}
+
+	toRef := v.RelPath(toPath)
+	if slices.Contains(doc.Header.GetList(KeyLinks), toRef) {
+		return false, nil
+	}
+
+	doc.Header.AppendList(KeyLinks, toRef)
+
+	return true, WriteDocument(fromPath, doc)
+}
+
+// LinkSources returns the vault-relative references of every note that links to the note at path, without the index
+func (v *Vault) LinkSources(path string) ([]string, error) {
+	docs, err := v.readAllDocuments()
+	if err != nil {
+		return nil, err
+	}
+
+	ref := v.RelPath(path)
+	sources := []string{}
+
+	for source, doc := range docs {
+		if source != path && slices.Contains(v.NoteLinks(source, doc), ref) {
+			sources = append(sources, v.RelPath(source))
+		}
+	}
+
+	sort.Strings(sources)
+
+	return sources, nil
+}
@@ -0,0 +1,65 @@
+package notes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Separate appended content from the existing body by a blank line
+func appendBody(body, addition string) string {
+	if strings.TrimSpace(body) == "" {
+		return addition
+	}
+
+	return strings.TrimRight(body, "\n") + "\n\n" + addition
+}
+
+// PlanMerge appends the body of fromPath to toPath, redirects every link to fromPath, then deletes fromPath
+func (v *Vault) PlanMerge(fromPath, toPath string) (Plan, error) {
+	if fromPath == toPath {
+		return nil, fmt.Errorf("%w: %s", ErrSameNote, v.RelPath(fromPath))
+	}
+
+	fromDoc, err := ReadDocument(fromPath)
+	if err != nil {
+		return nil, err
+	}
+
+	toDoc, err := ReadDocument(toPath)
+	if err != nil {
+		return nil, err
+	}
+
+	redirects := map[string]string{fromPath: toPath}
+	fromRef := v.RelPath(fromPath)
+
+	toDoc, _ = v.redirectDoc(toPath, toDoc, redirects)
+	fromDoc, _ = v.redirectDoc(fromPath, fromDoc, redirects)
+
+	toDoc.Body = appendBody(toDoc.Body, RelocateLinks(fromPath, toPath, fromDoc.Body))
+	toDoc.Header.AppendList(KeyMergedFrom, fromRef)
+	toDoc.Header.AppendList(KeyMergedFrom, fromDoc.Header.GetList(KeyMergedFrom)...)
+
+	toRef := v.RelPath(toPath)
+	for _, ref := range fromDoc.Header.GetList(KeyLinks) {
+		if ref != toRef {
+			toDoc.Header.AppendList(KeyLinks, ref)
+		}
+	}
+
+	plan := Plan{{
+		Path:        toPath,
+		Description: fmt.Sprintf("append the body of %s and record %s", fromRef, KeyMergedFrom),
+		Content:     toDoc.String(),
+	}}
+
+	redirectPlan, err := v.planRedirects(redirects, toPath)
+	if err != nil {
+		return nil, err
+	}
+
+	plan = append(plan, redirectPlan...)
+	plan = append(plan, Change{Path: fromPath, Description: "merged into " + toRef, Delete: true})
+
+	return plan, nil
+}
@@ -0,0 +1,55 @@
+package notes
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+)
+
+// KeyMovedFrom records the previous locations of a note that was moved between subDirs
+const KeyMovedFrom = "moved-from"
+
+var ErrSameSubDir = errors.New("note is already in the subDir")
+
+// PlanMove recreates the note in subDir, redirects every link to the previous location, then deletes the original
+func (v *Vault) PlanMove(path, subDir string) (Plan, error) {
+	if err := v.checkSubDir(subDir); err != nil {
+		return nil, err
+	}
+
+	toPath := filepath.Join(v.SyncDir, subDir, filepath.Base(path))
+	if toPath == path {
+		return nil, fmt.Errorf("%w: %s", ErrSameSubDir, v.RelPath(path))
+	} else if exists(toPath) {
+		return nil, fmt.Errorf("failed to move %s: %s already exists", v.RelPath(path), v.RelPath(toPath))
+	}
+
+	doc, err := ReadDocument(path)
+	if err != nil {
+		return nil, err
+	}
+
+	redirects := map[string]string{path: toPath}
+	fromRef := v.RelPath(path)
+
+	doc, _ = v.redirectDoc(path, doc, redirects)
+	doc.Body = RelocateLinks(path, toPath, doc.Body)
+	doc.Header.AppendList(KeyMovedFrom, fromRef)
+
+	plan := Plan{{
+		Path:        toPath,
+		Description: fmt.Sprintf("move from %s and record %s", fromRef, KeyMovedFrom),
+		Content:     doc.String(),
+		Create:      true,
+	}}
+
+	redirectPlan, err := v.planRedirects(redirects, toPath)
+	if err != nil {
+		return nil, err
+	}
+
+	plan = append(plan, redirectPlan...)
+	plan = append(plan, Change{Path: path, Description: "moved to " + subDir, Delete: true})
+
+	return plan, nil
+}
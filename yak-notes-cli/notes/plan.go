@@ -0,0 +1,205 @@
+package notes
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Change is a planned modification to a single file
+type Change struct {
+	Path        string
+	Description string
+	// Content replaces the file unless Delete is set
+	Content string
+	Create  bool
+	Delete  bool
+}
+
+// Plan is an ordered list of changes that can be printed for a dry-run before being applied
+type Plan []Change
+
+func (v *Vault) FormatPlan(plan Plan) string {
+	var b strings.Builder
+
+	for _, change := range plan {
+		action := "update"
+		if change.Create {
+			action = "create"
+		} else if change.Delete {
+			action = "delete"
+		}
+
+		fmt.Fprintf(&b, "%s %s: %s\n", action, v.RelPath(change.Path), change.Description)
+	}
+
+	return b.String()
+}
+
+// Apply writes every change in order
+func (v *Vault) Apply(plan Plan) error {
+	for _, change := range plan {
+		if change.Delete {
+			if err := os.Remove(change.Path); err != nil {
+				return fmt.Errorf("failed to delete %s: %w", v.RelPath(change.Path), err)
+			}
+
+			continue
+		}
+
+		write := writeFileAtomic
+		if change.Create {
+			write = writeFileExclusive
+		}
+
+		if err := write(change.Path, []byte(change.Content)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Create a new file with the complete content and fail if the path already exists
+//
+// The content is written to a temporary file that is hard linked to the path, so readers never see a partial file
+func writeFileExclusive(path string, data []byte) error {
+	tmp, err := writeTempFile(path, data, 0o644)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+
+	err = os.Link(tmp, path)
+	if err == nil {
+		return nil
+	} else if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	// Fallback for file systems without hard links
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Rewrite links in the body and in the header references for each redirected absolute path
+func (v *Vault) redirectDoc(path string, doc Document, redirects map[string]string) (Document, int) {
+	body, count := RewriteLinks(path, doc.Body, func(target string) (string, bool) {
+		newTarget, ok := redirects[target]
+		return newTarget, ok
+	})
+	doc.Body = body
+
+	for _, key := range referenceKeys {
+		refs := doc.Header.GetList(key)
+		changed := false
+
+		for i, ref := range refs {
+			if newTarget, ok := redirects[v.AbsPath(ref)]; ok {
+				refs[i] = v.RelPath(newTarget)
+				changed = true
+				count++
+			}
+		}
+
+		if changed {
+			doc.Header.SetList(key, refs)
+		}
+	}
+
+	return doc, count
+}
+
+// Remove links in the body and in the header references to each deleted absolute path
+func (v *Vault) unlinkDoc(path string, doc Document, deleted map[string]bool) (Document, int) {
+	body, count := StripLinks(path, doc.Body, func(target string) bool { return deleted[target] })
+	doc.Body = body
+
+	for _, key := range referenceKeys {
+		refs := doc.Header.GetList(key)
+		kept := slices.DeleteFunc(slices.Clone(refs), func(ref string) bool { return deleted[v.AbsPath(ref)] })
+
+		if len(kept) < len(refs) {
+			count += len(refs) - len(kept)
+
+			if len(kept) == 0 {
+				doc.Header.Delete(key)
+			} else {
+				doc.Header.SetList(key, kept)
+			}
+		}
+	}
+
+	return doc, count
+}
+
+// Plan the removal of links to the deleted absolute paths from every note in the vault
+func (v *Vault) planUnlinks(deleted map[string]bool) (Plan, error) {
+	stats, err := v.ListNotes("")
+	if err != nil {
+		return nil, err
+	}
+
+	plan := Plan{}
+
+	for _, stat := range stats {
+		doc, err := ReadDocument(stat.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		doc, count := v.unlinkDoc(stat.Path, doc, deleted)
+		if count > 0 {
+			plan = append(plan, Change{
+				Path:        stat.Path,
+				Description: fmt.Sprintf("remove %d link(s) to deleted notes", count),
+				Content:     doc.String(),
+			})
+		}
+	}
+
+	return plan, nil
+}
+
+// Plan link redirects for every note in the vault except those in skip
+func (v *Vault) planRedirects(redirects map[string]string, skip ...string) (Plan, error) {
+	stats, err := v.ListNotes("")
+	if err != nil {
+		return nil, err
+	}
+
+	plan := Plan{}
+
+	for _, stat := range stats {
+		if _, ok := redirects[stat.Path]; ok || slices.Contains(skip, stat.Path) {
+			continue
+		}
+
+		doc, err := ReadDocument(stat.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		doc, count := v.redirectDoc(stat.Path, doc, redirects)
+		if count > 0 {
+			plan = append(plan, Change{
+				Path:        stat.Path,
+				Description: fmt.Sprintf("redirect %d link(s)", count),
+				Content:     doc.String(),
+			})
+		}
+	}
+
+	return plan, nil
+}
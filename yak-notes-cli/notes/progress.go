@@ -0,0 +1,142 @@
+package notes
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// ClosedStates are the task states that count as done for the progress of a parent
+var ClosedStates = []State{StateComplete, StateNotPlanned}
+
+var taskItemRe = regexp.MustCompile(`(?m)^[ \t]*[-*+] \[([ xX])\] (.*)$`)
+
+// TaskItem is a djot task list item such as `- [x] Done`
+type TaskItem struct {
+	Done bool
+	Text string
+}
+
+// ParseTasks returns every task list item outside of code blocks
+func ParseTasks(body string) []TaskItem {
+	code := codeBlockRanges(body)
+	items := []TaskItem{}
+
+	for _, m := range taskItemRe.FindAllStringSubmatchIndex(body, -1) {
+		if !inRanges(code, m[0]) {
+			items = append(items, TaskItem{Done: body[m[2]:m[3]] != " ", Text: body[m[4]:m[5]]})
+		}
+	}
+
+	return items
+}
+
+// Progress is the rollup of the task list items and child task notes of a parent note
+type Progress struct {
+	State State
+	Done  int
+	Total int
+}
+
+func (p Progress) Open() int {
+	return p.Total - p.Done
+}
+
+// String is formatted as `done/total` or empty when there are no subtasks
+func (p Progress) String() string {
+	if p.Total == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%d/%d", p.Done, p.Total)
+}
+
+// Inconsistent is true when the parent is complete, but has open subtasks
+func (p Progress) Inconsistent() bool {
+	return p.State == StateComplete && p.Open() > 0
+}
+
+func isTaskState(state State) bool {
+	return slices.Contains(OpenStates, state) || slices.Contains(ClosedStates, state)
+}
+
+// Find the child task notes linked from the body that have a task state
+func childTasks(path string, doc Document, docs map[string]Document) map[string]State {
+	children := map[string]State{}
+
+	for _, link := range ParseLinks(doc.Body) {
+		target, ok := LinkTarget(path, link.Dest)
+		if !ok || target == path {
+			continue
+		}
+
+		child, ok := docs[target]
+		if !ok {
+			continue
+		}
+
+		if state, err := child.Header.State(); err == nil && isTaskState(state) {
+			children[target] = state
+		}
+	}
+
+	return children
+}
+
+// rollup counts the task list items and child task notes
+//
+// Task list items that link to a child task note are counted once from the state of the child
+func rollup(path string, doc Document, docs map[string]Document) Progress {
+	progress := Progress{}
+	if state, err := doc.Header.State(); err == nil {
+		progress.State = state
+	}
+
+	children := childTasks(path, doc, docs)
+
+	for _, item := range ParseTasks(doc.Body) {
+		linksChild := slices.ContainsFunc(ParseLinks(item.Text), func(link Link) bool {
+			target, ok := LinkTarget(path, link.Dest)
+			_, isChild := children[target]
+
+			return ok && isChild
+		})
+		if linksChild {
+			continue
+		}
+
+		progress.Total++
+
+		if item.Done {
+			progress.Done++
+		}
+	}
+
+	for _, state := range children {
+		progress.Total++
+
+		if slices.Contains(ClosedStates, state) {
+			progress.Done++
+		}
+	}
+
+	return progress
+}
+
+// TaskProgress returns the progress of every note with subtasks keyed by path
+func (v *Vault) TaskProgress() (map[string]Progress, error) {
+	docs, err := v.readAllDocuments()
+	if err != nil {
+		return nil, err
+	}
+
+	progress := map[string]Progress{}
+
+	for path, doc := range docs {
+		if p := rollup(path, doc, docs); p.Total > 0 {
+			progress[path] = p
+		}
+	}
+
+	return progress, nil
+}
@@ -0,0 +1,282 @@
+package notes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/djherbis/times"
+)
+
+// CreationSource describes where the creation time of a note was read from
+type CreationSource string
+
+const (
+	CreationBirthTime CreationSource = "file birth time"
+	CreationHeader    CreationSource = "creation_date header"
+	CreationName      CreationSource = "timestamp in the file name"
+	CreationModTime   CreationSource = "modification time"
+)
+
+var (
+	ErrNoCreationTime = errors.New("no trustworthy creation time")
+
+	timeNameRe = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}_\d{2}_\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:\d{2})`)
+
+	// Times before this are treated as unset, such as the Unix epoch on some file systems
+	minTrustedTime = time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func isTrusted(t, now time.Time) bool {
+	return !t.IsZero() && t.After(minTrustedTime) && !t.After(now)
+}
+
+// Parse the first timestamp in a name created with ToTimeName
+func timeFromName(name string) (time.Time, bool) {
+	match := timeNameRe.FindString(name)
+	if match == "" {
+		return time.Time{}, false
+	}
+
+	t, err := FromTimeName(match)
+
+	return t, err == nil
+}
+
+// CreationTime reads the creation time of the note at path and reports the source
+//
+// Explicit metadata is preferred: the `creation_date` header, then a timestamp in the name.
+// The file birth time is next, but is missing or wrong on many Linux file systems, then the modification time
+func CreationTime(path string) (time.Time, CreationSource, error) {
+	ts, err := times.Stat(path)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("error with specified file (`%s`): %w", path, err)
+	}
+
+	doc, err := ReadDocument(path)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+
+	now := time.Now()
+
+	if t, ok, err := doc.Header.GetTime(KeyCreationDate); err == nil && ok && isTrusted(t, now) {
+		return t, CreationHeader, nil
+	}
+
+	if t, ok := timeFromName(filepath.Base(path)); ok && isTrusted(t, now) {
+		return t, CreationName, nil
+	}
+
+	if ts.HasBirthTime() {
+		if birth := ts.BirthTime(); isTrusted(birth, now) && !birth.After(ts.ModTime()) {
+			return birth, CreationBirthTime, nil
+		}
+	}
+
+	if modTime := ts.ModTime(); isTrusted(modTime, now) {
+		return modTime, CreationModTime, nil
+	}
+
+	return time.Time{}, "", fmt.Errorf("%w for %s", ErrNoCreationTime, path)
+}
+
+// IsTimeName is true when the file name without the extension was created by ToTimeName
+func IsTimeName(name string) bool {
+	_, err := FromTimeName(strings.TrimSuffix(name, filepath.Ext(name)))
+	return err == nil
+}
+
+// Rename without replacing an existing file
+func renameExclusive(fromPath, toPath string) error {
+	err := os.Link(fromPath, toPath)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("failed to rename file from %s to %s: %w", fromPath, toPath, err)
+	} else if err != nil {
+		// Fallback for file systems without hard links
+		if exists(toPath) {
+			return fmt.Errorf("failed to rename file from %s to %s: %w", fromPath, toPath, os.ErrExist)
+		}
+
+		if err := os.Rename(fromPath, toPath); err != nil {
+			return fmt.Errorf("failed to rename file from %s to %s: %w", fromPath, toPath, err)
+		}
+
+		return nil
+	}
+
+	if err := os.Remove(fromPath); err != nil {
+		return fmt.Errorf("failed to remove %s after renaming: %w", fromPath, err)
+	}
+
+	return nil
+}
+
+// Rename is a planned change of a file name to the creation time
+type Rename struct {
+	From   string         `json:"from"`
+	To     string         `json:"to"`
+	Source CreationSource `json:"source"`
+}
+
+// RenamePlan is an ordered list of renames that can be printed for a dry-run before being applied
+type RenamePlan []Rename
+
+// The first free name for the time with a suffix when another file has the same time name
+func availableTimeName(dir string, t time.Time, ext string, reserved map[string]bool) string {
+	path := filepath.Join(dir, ToTimeName(t)+ext)
+
+	for n := 2; exists(path) || reserved[path]; n++ {
+		path = filepath.Join(dir, SuffixedTimeName(t, n)+ext)
+	}
+
+	return path
+}
+
+// PlanRenames plans a time name for each path based on the creation time and resolves collisions with a suffix
+func PlanRenames(paths []string) (RenamePlan, error) {
+	plan := RenamePlan{}
+	reserved := map[string]bool{}
+
+	for _, path := range paths {
+		cTime, source, err := CreationTime(path)
+		if err != nil {
+			return nil, err
+		}
+
+		ext := filepath.Ext(path)
+
+		toPath := filepath.Join(filepath.Dir(path), ToTimeName(cTime)+ext)
+		if toPath != path {
+			toPath = availableTimeName(filepath.Dir(path), cTime, ext, reserved)
+		}
+
+		reserved[toPath] = true
+		plan = append(plan, Rename{From: path, To: toPath, Source: source})
+	}
+
+	return plan, nil
+}
+
+// PlanRenameAll plans time names for every note in dir that doesn't already have one
+func PlanRenameAll(dir string) (RenamePlan, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", dir, err)
+	}
+
+	paths := []string{}
+
+	for _, entry := range entries {
+		if !entry.IsDir() && IsNoteFile(entry.Name()) && !IsTimeName(entry.Name()) {
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+
+	return PlanRenames(paths)
+}
+
+func (p RenamePlan) String() string {
+	var b strings.Builder
+
+	for _, r := range p {
+		fmt.Fprintf(&b, "rename %s to %s (from the %s)\n", r.From, filepath.Base(r.To), r.Source)
+	}
+
+	return b.String()
+}
+
+// Revert the renames in reverse order
+func (p RenamePlan) revert() error {
+	var errs []error
+
+	for i := len(p) - 1; i >= 0; i-- {
+		if err := renameExclusive(p[i].To, p[i].From); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Apply renames every file after writing the undo log. All renames are reverted when any fails
+func (p RenamePlan) Apply(undoLog string) error {
+	if len(p) == 0 {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the undo log: %w", err)
+	}
+
+	if err := writeFileExclusive(undoLog, data); err != nil {
+		return fmt.Errorf("failed to write the undo log: %w", err)
+	}
+
+	for i, r := range p {
+		if r.From == r.To {
+			continue
+		}
+
+		if err := renameExclusive(r.From, r.To); err != nil {
+			if revertErr := p[:i].revert(); revertErr != nil {
+				return fmt.Errorf("%w (and failed to revert: %w)", err, revertErr)
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UndoRenames reverts the renames recorded in the undo log, then removes the log
+func UndoRenames(undoLog string) (RenamePlan, error) {
+	data, err := os.ReadFile(undoLog)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the undo log: %w", err)
+	}
+
+	var plan RenamePlan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse the undo log %s: %w", undoLog, err)
+	}
+
+	if err := plan.revert(); err != nil {
+		return nil, err
+	}
+
+	if err := os.Remove(undoLog); err != nil {
+		return nil, fmt.Errorf("failed to remove the undo log: %w", err)
+	}
+
+	return plan, nil
+}
+
+// UndoLogPath is where the undo log for renames in dir is written
+func UndoLogPath(dir string, now time.Time) string {
+	return filepath.Join(dir, ".rename-undo-"+ToTimeName(now)+".json")
+}
+
+// RenameNote renames the file at path based on the creation time and returns the new path and the source
+func (v *Vault) RenameNote(path string) (string, CreationSource, error) {
+	plan, err := PlanRenames([]string{path})
+	if err != nil {
+		return "", "", err
+	}
+
+	rename := plan[0]
+	if rename.From != rename.To {
+		if err := renameExclusive(rename.From, rename.To); err != nil {
+			return "", "", fmt.Errorf("failed to rename file: %w", err)
+		}
+	}
+
+	return rename.To, rename.Source, nil
+}
@@ -0,0 +1,216 @@
+package notes
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Header keys for recurring tasks
+const (
+	KeyRepeat       = "repeat"
+	KeyRepeatedFrom = "repeated-from"
+)
+
+// Frequencies supported by the `repeat` rule
+const (
+	RepeatDaily   = "daily"
+	RepeatWeekly  = "weekly"
+	RepeatMonthly = "monthly"
+)
+
+var (
+	ErrInvalidRepeat = errors.New("invalid repeat rule")
+
+	weekdays = map[string]time.Weekday{
+		"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+		"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+	}
+
+	checkedTaskRe = regexp.MustCompile(`(?m)^([ \t]*[-*+] \[)[xX](\])`)
+)
+
+// Repeat is a rule such as `daily`, `weekly:mon,thu`, or `monthly:15`
+type Repeat struct {
+	Frequency  string
+	Weekdays   []time.Weekday
+	DayOfMonth int
+}
+
+func ParseRepeat(value string) (Repeat, error) {
+	frequency, args, _ := strings.Cut(strings.ToLower(strings.TrimSpace(value)), ":")
+	rule := Repeat{Frequency: frequency}
+
+	switch frequency {
+	case RepeatDaily:
+		if args != "" {
+			return Repeat{}, fmt.Errorf("%w: '%s' does not accept arguments", ErrInvalidRepeat, value)
+		}
+	case RepeatWeekly:
+		for name := range strings.SplitSeq(args, listSep) {
+			if name = strings.TrimSpace(name); name == "" {
+				continue
+			}
+
+			day, ok := weekdays[name[:min(3, len(name))]]
+			if !ok {
+				return Repeat{}, fmt.Errorf("%w: unknown weekday '%s' in '%s'", ErrInvalidRepeat, name, value)
+			}
+
+			rule.Weekdays = append(rule.Weekdays, day)
+		}
+	case RepeatMonthly:
+		if args != "" {
+			day, err := strconv.Atoi(args)
+			if err != nil || day < 1 || day > 31 {
+				return Repeat{}, fmt.Errorf("%w: day of month must be 1-31 in '%s'", ErrInvalidRepeat, value)
+			}
+
+			rule.DayOfMonth = day
+		}
+	default:
+		return Repeat{}, fmt.Errorf(
+			"%w: '%s' must start with one of %s, %s, or %s",
+			ErrInvalidRepeat, value, RepeatDaily, RepeatWeekly, RepeatMonthly,
+		)
+	}
+
+	return rule, nil
+}
+
+// The date in the month, clamped to the last day of shorter months
+func clampedDate(year int, month time.Month, day int) time.Time {
+	lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	return time.Date(year, month, min(day, lastDay), 0, 0, 0, 0, time.UTC)
+}
+
+// Pinned fixes the day of a monthly rule without one to the day of the first occurrence, so that the day is kept after
+// it's clamped in a shorter month
+func (r Repeat) Pinned(first time.Time) Repeat {
+	if r.Frequency == RepeatMonthly && r.DayOfMonth == 0 {
+		r.DayOfMonth = calendarDate(first).Day()
+	}
+
+	return r
+}
+
+// Next returns the first calendar date of the rule after the date of after
+func (r Repeat) Next(after time.Time) time.Time {
+	day := calendarDate(after)
+
+	switch r.Frequency {
+	case RepeatWeekly:
+		if len(r.Weekdays) == 0 {
+			return day.AddDate(0, 0, 7)
+		}
+
+		for offset := 1; ; offset++ {
+			next := day.AddDate(0, 0, offset)
+			for _, weekday := range r.Weekdays {
+				if next.Weekday() == weekday {
+					return next
+				}
+			}
+		}
+	case RepeatMonthly:
+		dayOfMonth := r.DayOfMonth
+		if dayOfMonth == 0 {
+			dayOfMonth = day.Day()
+		}
+
+		if next := clampedDate(day.Year(), day.Month(), dayOfMonth); next.After(day) {
+			return next
+		}
+
+		return clampedDate(day.Year(), day.Month()+1, dayOfMonth)
+	default:
+		return day.AddDate(0, 0, 1)
+	}
+}
+
+// Move the date in the header by the number of days, keeping date-only values as dates
+func shiftDate(header *Header, key string, days int) error {
+	t, ok, err := header.GetTime(key)
+	if err != nil || !ok {
+		return err
+	}
+
+	value, _ := header.Get(key)
+	if len(value) == len(time.DateOnly) {
+		header.Set(key, t.AddDate(0, 0, days).Format(time.DateOnly))
+	} else {
+		header.SetTime(key, t.AddDate(0, 0, days))
+	}
+
+	return nil
+}
+
+// Plan the next occurrence of a completed recurring task
+func (v *Vault) planNextOccurrence(path string, doc Document, completed time.Time) (Change, error) {
+	value, _ := doc.Header.Get(KeyRepeat)
+
+	rule, err := ParseRepeat(value)
+	if err != nil {
+		return Change{}, fmt.Errorf("failed to repeat %s: %w", v.RelPath(path), err)
+	}
+
+	schedule, err := doc.Header.Schedule()
+	if err != nil {
+		return Change{}, fmt.Errorf("failed to repeat %s: %w", v.RelPath(path), err)
+	}
+
+	anchor := schedule.Deadline()
+	if anchor.IsZero() {
+		anchor = schedule.Start
+	}
+
+	if anchor.IsZero() {
+		anchor = completed
+	}
+
+	// The next occurrence is written with the pinned day, since its own date can be clamped
+	if pinned := rule.Pinned(anchor); pinned.DayOfMonth != rule.DayOfMonth {
+		rule, value = pinned, fmt.Sprintf("%s:%d", RepeatMonthly, pinned.DayOfMonth)
+	}
+
+	// A task completed late repeats from the completion date, so that the next occurrence isn't already overdue
+	next := rule.Next(anchor)
+	for !next.After(calendarDate(completed)) {
+		next = rule.Next(next)
+	}
+
+	days := int(next.Sub(calendarDate(anchor)).Hours() / 24)
+
+	nextDoc := Document{Body: checkedTaskRe.ReplaceAllString(doc.Body, "$1 $2")}
+	nextDoc.Header.Set(KeyRepeat, value)
+	nextDoc.Header.SetState(StateQueue)
+
+	if schedule.IsZero() {
+		nextDoc.Header.Set(KeyStartDate, next.Format(time.DateOnly))
+	} else {
+		for _, key := range []string{KeyStartDate, KeySoftDeadline, KeyHardDeadline} {
+			if dateValue, ok := doc.Header.Get(key); ok {
+				nextDoc.Header.Set(key, dateValue)
+
+				if err := shiftDate(&nextDoc.Header, key, days); err != nil {
+					return Change{}, err
+				}
+			}
+		}
+	}
+
+	nextDoc.Header.Set(KeyRepeatedFrom, v.RelPath(path))
+
+	nextPath := availableTimeName(filepath.Dir(path), completed, NoteExt, nil)
+
+	return Change{
+		Path:        nextPath,
+		Description: fmt.Sprintf("next occurrence on %s", next.Format(time.DateOnly)),
+		Content:     nextDoc.String(),
+		Create:      true,
+	}, nil
+}
@@ -0,0 +1,164 @@
+package notes
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultSplitMarker is a djot comment that explicitly separates atomic notes
+const DefaultSplitMarker = "{% split %}"
+
+var (
+	ErrNothingToSplit = errors.New("no headings or split markers were found")
+
+	headingRe = regexp.MustCompile(`^(#{1,6})[ \t]+(.*)$`)
+)
+
+type section struct {
+	title string
+	body  string
+}
+
+// Split the body at each matching line. The line is kept as the start of the section when keep is set
+func splitLines(body string, match func(line string) (string, bool), keep bool) (string, []section) {
+	code := codeBlockRanges(body)
+	preamble, sections := "", []section{}
+	offset := 0
+
+	for line := range strings.SplitAfterSeq(body, "\n") {
+		start := offset
+		offset += len(line)
+
+		title, ok := match(strings.TrimRight(line, "\r\n"))
+		if ok && !inRanges(code, start) {
+			sections = append(sections, section{title: title})
+
+			if !keep {
+				continue
+			}
+		}
+
+		if len(sections) == 0 {
+			preamble += line
+		} else {
+			sections[len(sections)-1].body += line
+		}
+	}
+
+	return preamble, sections
+}
+
+// The shallowest heading level in the body outside of code blocks
+func topHeadingLevel(body string) int {
+	code := codeBlockRanges(body)
+	level, offset := 0, 0
+
+	for line := range strings.SplitAfterSeq(body, "\n") {
+		if m := headingRe.FindStringSubmatch(strings.TrimRight(line, "\r\n")); m != nil && !inRanges(code, offset) {
+			if level == 0 || len(m[1]) < level {
+				level = len(m[1])
+			}
+		}
+
+		offset += len(line)
+	}
+
+	return level
+}
+
+func firstLine(text string) string {
+	for line := range strings.SplitSeq(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+
+	return ""
+}
+
+func splitSections(body, marker string) (string, []section) {
+	if marker != "" && strings.Contains(body, marker) {
+		preamble, sections := splitLines(body, func(line string) (string, bool) {
+			return "", strings.TrimSpace(line) == marker
+		}, false)
+
+		for i := range sections {
+			sections[i].title = firstLine(sections[i].body)
+		}
+
+		return preamble, sections
+	}
+
+	level := topHeadingLevel(body)
+	if level == 0 {
+		return body, nil
+	}
+
+	return splitLines(body, func(line string) (string, bool) {
+		m := headingRe.FindStringSubmatch(line)
+		if m == nil || len(m[1]) != level {
+			return "", false
+		}
+
+		return strings.TrimSpace(m[2]), true
+	}, true)
+}
+
+func escapeLinkText(text string) string {
+	return strings.NewReplacer("[", `\[`, "]", `\]`).Replace(text)
+}
+
+// PlanSplit creates a note for each section and replaces the original body with an index of links
+//
+// Sections are separated by the marker when present, otherwise by the top-level djot headings
+func (v *Vault) PlanSplit(path, marker string) (Plan, error) {
+	doc, err := ReadDocument(path)
+	if err != nil {
+		return nil, err
+	}
+
+	preamble, sections := splitSections(doc.Body, marker)
+	if len(sections) == 0 {
+		return nil, fmt.Errorf("%w in %s", ErrNothingToSplit, v.RelPath(path))
+	}
+
+	ref := v.RelPath(path)
+	reserved := map[string]bool{}
+	plan := Plan{}
+	index := []string{}
+	now := time.Now()
+
+	for _, sec := range sections {
+		newPath := availableTimeName(filepath.Dir(path), now, NoteExt, reserved)
+		reserved[newPath] = true
+
+		newDoc := Document{Body: strings.TrimRight(sec.body, "\n") + "\n"}
+		newDoc.Header.Set(KeySplitFrom, ref)
+
+		title := sec.title
+		if title == "" {
+			title = filepath.Base(newPath)
+		}
+
+		plan = append(plan, Change{
+			Path:        newPath,
+			Description: fmt.Sprintf("split '%s' from %s", title, ref),
+			Content:     newDoc.String(),
+			Create:      true,
+		})
+		index = append(index, fmt.Sprintf("- [%s](%s)\n", escapeLinkText(title), LinkDest(path, newPath)))
+	}
+
+	doc.Body = appendBody(preamble, strings.Join(index, ""))
+	plan = append(plan, Change{
+		Path:        path,
+		Description: fmt.Sprintf("replace the body with an index of %d note(s)", len(sections)),
+		Content:     doc.String(),
+	})
+
+	return plan, nil
+}
@@ -0,0 +1,3 @@
+-- sqlfluff:dialect:duckdb
+-- sqlfluff:templater:placeholder:param_style:colon
+DELETE FROM embedding WHERE embedding.filename = :filename;
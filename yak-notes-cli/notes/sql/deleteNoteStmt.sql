@@ -0,0 +1,3 @@
+-- sqlfluff:dialect:duckdb
+-- sqlfluff:templater:placeholder:param_style:colon
+DELETE FROM note WHERE note.filename = :filename;
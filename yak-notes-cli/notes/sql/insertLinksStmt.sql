@@ -0,0 +1,3 @@
+-- sqlfluff:dialect:duckdb
+-- sqlfluff:templater:placeholder:param_style:colon
+INSERT INTO link (source, target) VALUES (:source, :target)
@@ -0,0 +1,5 @@
+-- sqlfluff:dialect:duckdb
+-- sqlfluff:templater:placeholder:param_style:colon
+INSERT INTO note (sub_dir, filename, title, content, modified_at) VALUES (
+    :sub_dir, :filename, :title, :content, :modified_at
+)
@@ -0,0 +1,21 @@
+-- sqlfluff:dialect:duckdb
+-- sqlfluff:templater:placeholder:param_style:colon
+SELECT
+    note.sub_dir,
+    note.filename,
+    note.title,
+    note.content,
+    note.modified_at
+FROM note
+WHERE
+    note.title ILIKE '%' || :query || '%'
+    OR EXISTS (
+        SELECT 1
+        FROM embedding
+        WHERE
+            embedding.filename = note.filename
+            AND embedding.embedding ILIKE '%' || :query || '%'
+    )
+-- Order by match quality, with title matches first, then by the most recently modified
+ORDER BY note.title ILIKE '%' || :query || '%' DESC, note.modified_at DESC
+LIMIT :limit_ OFFSET :offset_;
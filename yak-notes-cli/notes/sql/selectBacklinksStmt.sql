@@ -0,0 +1,6 @@
+-- sqlfluff:dialect:duckdb
+-- sqlfluff:templater:placeholder:param_style:colon
+SELECT DISTINCT link.source
+FROM link
+WHERE link.target = :target AND link.source != :target
+ORDER BY link.source;
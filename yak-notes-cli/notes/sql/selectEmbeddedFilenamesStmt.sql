@@ -0,0 +1,4 @@
+-- sqlfluff:dialect:duckdb
+SELECT DISTINCT embedding.filename
+FROM embedding
+ORDER BY embedding.filename;
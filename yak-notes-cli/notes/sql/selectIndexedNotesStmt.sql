@@ -0,0 +1,6 @@
+-- sqlfluff:dialect:duckdb
+SELECT
+    note.sub_dir,
+    note.filename
+FROM note
+ORDER BY note.sub_dir, note.filename;
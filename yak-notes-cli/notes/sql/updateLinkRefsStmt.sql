@@ -0,0 +1,6 @@
+-- sqlfluff:dialect:duckdb
+-- sqlfluff:templater:placeholder:param_style:colon
+UPDATE link SET
+    source = CASE WHEN link.source = :from_ref THEN :to_ref ELSE link.source END,
+    target = CASE WHEN link.target = :from_ref THEN :to_ref ELSE link.target END
+WHERE link.source = :from_ref OR link.target = :from_ref;
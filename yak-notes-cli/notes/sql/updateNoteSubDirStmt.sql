@@ -0,0 +1,3 @@
+-- sqlfluff:dialect:duckdb
+-- sqlfluff:templater:placeholder:param_style:colon
+UPDATE note SET sub_dir = :sub_dir WHERE note.filename = :filename;
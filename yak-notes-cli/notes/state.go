@@ -0,0 +1,55 @@
+package notes
+
+import (
+	"fmt"
+	"time"
+)
+
+// KeyStateChanged records when the state was last set
+const KeyStateChanged = "state-changed"
+
+// StateChange describes the result of SetState
+type StateChange struct {
+	Previous State
+	// NextOccurrence is the path of the note created when a recurring task is completed
+	NextOccurrence string
+}
+
+// SetState records the state and the transition time in the note header
+//
+// When a note with a `repeat` rule is completed, the next occurrence is created
+func (v *Vault) SetState(path string, state State) (StateChange, error) {
+	doc, err := ReadDocument(path)
+	if err != nil {
+		return StateChange{}, err
+	}
+
+	previous, err := doc.Header.State()
+	if err != nil {
+		return StateChange{}, fmt.Errorf("failed to read the current state of %s: %w", v.RelPath(path), err)
+	}
+
+	change := StateChange{Previous: previous}
+	now := time.Now()
+
+	doc.Header.SetState(state)
+	doc.Header.SetTime(KeyStateChanged, now)
+
+	plan := Plan{{Path: path, Description: "set state to " + string(state), Content: doc.String()}}
+
+	if _, ok := doc.Header.Get(KeyRepeat); ok && state == StateComplete && previous != StateComplete {
+		next, err := v.planNextOccurrence(path, doc, now)
+		if err != nil {
+			return change, err
+		}
+
+		plan = append(plan, next)
+		change.NextOccurrence = next.Path
+	}
+
+	if err := v.Apply(plan); err != nil {
+		return change, err
+	}
+
+	return change, nil
+}
@@ -0,0 +1,300 @@
+package notes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Count is the number of notes in a group, such as a subDir or a state
+type Count struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// WeekActivity is what was written in the week starting on Week (a Monday)
+type WeekActivity struct {
+	Week string `json:"week"`
+	// Created is the number of notes created in the week, based on the name
+	Created int `json:"created"`
+	// Modified is the number of notes last modified in the week
+	Modified int `json:"modified"`
+	// Words is the number of words written in the week, from the word log
+	Words int `json:"words"`
+}
+
+// LinkCount is the number of notes that link to a note and that the note links to
+type LinkCount struct {
+	Note     string `json:"note"`
+	Title    string `json:"title"`
+	Inbound  int    `json:"inbound"`
+	Outbound int    `json:"outbound"`
+}
+
+// VaultStats summarizes the notes and the writing activity of a vault
+type VaultStats struct {
+	Notes   int            `json:"notes"`
+	Words   int            `json:"words"`
+	SubDirs []Count        `json:"subDirs"`
+	States  []Count        `json:"states"`
+	Weeks   []WeekActivity `json:"weeks"`
+	// MostLinked are the notes with the most inbound links
+	MostLinked []LinkCount `json:"mostLinked"`
+	// MostOrphaned are the notes without inbound links, with the fewest outbound links and oldest first
+	MostOrphaned []LinkCount `json:"mostOrphaned"`
+}
+
+// NoState is the group of notes without a state header
+const NoState = "(none)"
+
+// The file in the sync directory that records the word counts between runs of Stats
+const wordLogFilename = ".word-counts.json"
+
+// The words written per week, found from the change in the word count of each note since the last run of Stats
+//
+// The words of a note that isn't in the log are written in the week it was created, and later additions in the week
+// it was last modified. Removed words aren't subtracted, and notes are kept by name after they are removed, so that a
+// moved or restored note isn't counted again
+type wordLog struct {
+	Notes map[string]int `json:"notes"`
+	Weeks map[string]int `json:"weeks"`
+}
+
+func (v *Vault) readWordLog() (wordLog, error) {
+	log := wordLog{Notes: map[string]int{}, Weeks: map[string]int{}}
+	path := filepath.Join(v.SyncDir, wordLogFilename)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return log, nil
+	} else if err != nil {
+		return log, fmt.Errorf("failed to read the word log: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &log); err != nil {
+		return log, fmt.Errorf("failed to parse the word log %s: %w", path, err)
+	}
+
+	if log.Notes == nil {
+		log.Notes = map[string]int{}
+	}
+
+	if log.Weeks == nil {
+		log.Weeks = map[string]int{}
+	}
+
+	return log, nil
+}
+
+func (v *Vault) writeWordLog(log wordLog) error {
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the word log: %w", err)
+	}
+
+	if err := writeFileAtomic(filepath.Join(v.SyncDir, wordLogFilename), data); err != nil {
+		return fmt.Errorf("failed to write the word log: %w", err)
+	}
+
+	return nil
+}
+
+// Truncate to the Monday of the week
+func weekStart(t time.Time) time.Time {
+	day := calendarDate(t)
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+func weekKey(t time.Time) string {
+	return weekStart(t).Format(time.DateOnly)
+}
+
+// Count the words in the text, skipping markup such as heading markers and bullets
+func countWords(text string) int {
+	count := 0
+
+	for _, field := range strings.Fields(text) {
+		if strings.IndexFunc(field, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) >= 0 {
+			count++
+		}
+	}
+
+	return count
+}
+
+// The creation time from the name, falling back to the modification time for notes that haven't been renamed
+func creationTime(stat FileStat) time.Time {
+	if created, err := FromTimeName(strings.TrimSuffix(stat.Name, NoteExt)); err == nil {
+		return created
+	}
+
+	return stat.ModTime
+}
+
+func sortedCounts(counts map[string]int) []Count {
+	sorted := []Count{}
+	for name, count := range counts {
+		sorted = append(sorted, Count{Name: name, Count: count})
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	return sorted
+}
+
+// Stats counts the notes and the weekly activity and lists up to top of the most linked and orphaned notes. The word
+// log is updated with the words written since the last run
+func (v *Vault) Stats(top int) (VaultStats, error) {
+	stats, err := v.ListNotes("")
+	if err != nil {
+		return VaultStats{}, err
+	}
+
+	log, err := v.readWordLog()
+	if err != nil {
+		return VaultStats{}, err
+	}
+
+	subDirs, err := v.ListSubDirs()
+	if err != nil {
+		return VaultStats{}, err
+	}
+
+	result := VaultStats{Notes: len(stats)}
+	subDirCounts := map[string]int{}
+	stateCounts := map[string]int{}
+	weeks := map[string]*WeekActivity{}
+	links := map[string]*LinkCount{}
+	sources := map[string]map[string]bool{}
+	created := map[string]time.Time{}
+
+	for _, subDir := range subDirs {
+		subDirCounts[subDir] = 0
+	}
+
+	week := func(key string) *WeekActivity {
+		if weeks[key] == nil {
+			weeks[key] = &WeekActivity{Week: key}
+		}
+
+		return weeks[key]
+	}
+
+	for _, stat := range stats {
+		doc, err := ReadDocument(stat.Path)
+		if err != nil {
+			return VaultStats{}, err
+		}
+
+		ref := v.RelPath(stat.Path)
+		words := countWords(doc.Body)
+
+		state, _ := doc.Header.Get(KeyState)
+		if state == "" {
+			state = NoState
+		}
+
+		subDirCounts[stat.SubDir]++
+		stateCounts[state]++
+		result.Words += words
+
+		created[ref] = creationTime(stat)
+		week(weekKey(created[ref])).Created++
+		week(weekKey(stat.ModTime)).Modified++
+
+		if previous, ok := log.Notes[stat.Name]; !ok {
+			log.Weeks[weekKey(created[ref])] += words
+		} else if words > previous {
+			log.Weeks[weekKey(stat.ModTime)] += words - previous
+		}
+
+		log.Notes[stat.Name] = words
+
+		links[ref] = &LinkCount{Note: ref, Title: doc.Title()}
+		targets := map[string]bool{}
+
+		for _, target := range v.NoteLinks(stat.Path, doc) {
+			if target == ref || targets[target] {
+				continue
+			}
+
+			targets[target] = true
+
+			if sources[target] == nil {
+				sources[target] = map[string]bool{}
+			}
+
+			sources[target][ref] = true
+		}
+
+		links[ref].Outbound = len(targets)
+	}
+
+	if err := v.writeWordLog(log); err != nil {
+		return VaultStats{}, err
+	}
+
+	for key, words := range log.Weeks {
+		week(key).Words = words
+	}
+
+	result.SubDirs = sortedCounts(subDirCounts)
+	result.States = sortedCounts(stateCounts)
+
+	result.Weeks = []WeekActivity{}
+	for _, activity := range weeks {
+		result.Weeks = append(result.Weeks, *activity)
+	}
+
+	sort.Slice(result.Weeks, func(i, j int) bool { return result.Weeks[i].Week < result.Weeks[j].Week })
+
+	linked := []LinkCount{}
+	orphaned := []LinkCount{}
+
+	for ref, count := range links {
+		count.Inbound = len(sources[ref])
+		if count.Inbound > 0 {
+			linked = append(linked, *count)
+		} else {
+			orphaned = append(orphaned, *count)
+		}
+	}
+
+	sort.Slice(linked, func(i, j int) bool {
+		if linked[i].Inbound != linked[j].Inbound {
+			return linked[i].Inbound > linked[j].Inbound
+		}
+
+		return linked[i].Note < linked[j].Note
+	})
+	sort.Slice(orphaned, func(i, j int) bool {
+		if orphaned[i].Outbound != orphaned[j].Outbound {
+			return orphaned[i].Outbound < orphaned[j].Outbound
+		}
+
+		if !created[orphaned[i].Note].Equal(created[orphaned[j].Note]) {
+			return created[orphaned[i].Note].Before(created[orphaned[j].Note])
+		}
+
+		return orphaned[i].Note < orphaned[j].Note
+	})
+
+	top = max(top, 0)
+	result.MostLinked = linked[:min(top, len(linked))]
+	result.MostOrphaned = orphaned[:min(top, len(orphaned))]
+
+	return result, nil
+}
@@ -0,0 +1,125 @@
+package notes
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TemplatesDir is the hidden folder in the sync directory that contains note templates
+const TemplatesDir = ".templates"
+
+// DefaultTemplate is used for subDirs that don't have a template of the same name
+const DefaultTemplate = "default"
+
+var ErrTemplateNotFound = errors.New("template not found")
+
+// TemplateData are the variables available when rendering a template
+type TemplateData struct {
+	// Time is the creation time, which can be formatted with `{{.Time.Format "Jan 2"}}`
+	Time time.Time
+	// Created is the creation time formatted as RFC3339
+	Created string
+	// Date is the creation date formatted as `2006-01-02`
+	Date   string
+	SubDir string
+	Title  string
+}
+
+func NewTemplateData(created time.Time, subDir, title string) TemplateData {
+	return TemplateData{
+		Time:    created,
+		Created: created.Format(time.RFC3339),
+		Date:    created.Format(time.DateOnly),
+		SubDir:  subDir,
+		Title:   title,
+	}
+}
+
+// TemplatePath is the file for the named template
+func (v *Vault) TemplatePath(name string) string {
+	return filepath.Join(v.SyncDir, TemplatesDir, name+NoteExt)
+}
+
+func (v *Vault) readTemplate(name string) (string, error) {
+	if name == "" || filepath.Base(name) != name {
+		return "", fmt.Errorf("%w: '%s'", ErrTemplateNotFound, name)
+	}
+
+	content, err := os.ReadFile(v.TemplatePath(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%w: '%s' in %s", ErrTemplateNotFound, name, filepath.Join(v.SyncDir, TemplatesDir))
+	} else if err != nil {
+		return "", fmt.Errorf("failed to read template %s: %w", name, err)
+	}
+
+	return string(content), nil
+}
+
+// SubDirTemplate is the template named after the subDir, then the default, or empty when neither exist
+func (v *Vault) SubDirTemplate(subDir string) string {
+	for _, name := range []string{subDir, DefaultTemplate} {
+		if exists(v.TemplatePath(name)) {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// TemplateUsesTitle is true when the named template references `.Title`
+func (v *Vault) TemplateUsesTitle(name string) (bool, error) {
+	content, err := v.readTemplate(name)
+	if err != nil {
+		return false, err
+	}
+
+	return strings.Contains(content, ".Title"), nil
+}
+
+// RenderTemplate renders the named template, which may include header fields, with the data
+func (v *Vault) RenderTemplate(name string, data TemplateData) (string, error) {
+	content, err := v.readTemplate(name)
+	if err != nil {
+		return "", err
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render template %s: %w", name, err)
+	}
+
+	return b.String(), nil
+}
+
+// FillNote records the creation time and optional title in the header of the content, then appends the body
+func FillNote(content string, created time.Time, title, body string) string {
+	doc := ParseDocument(content)
+	if _, ok := doc.Header.Get(KeyCreationDate); !ok {
+		doc.Header.SetTime(KeyCreationDate, created)
+	}
+
+	if title != "" {
+		doc.Header.Set(KeyName, title)
+	}
+
+	if body != "" {
+		if !strings.HasSuffix(body, "\n") {
+			body += "\n"
+		}
+
+		doc.Body = appendBody(doc.Body, body)
+	}
+
+	return doc.String()
+}
@@ -0,0 +1,205 @@
+package notes
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+)
+
+var (
+	anchoredLinkRe = regexp.MustCompile(`^` + inlineLinkRe.String())
+	listItemRe     = regexp.MustCompile(`^([ \t]*)([-*+]|\d+[.)])[ \t]+(.*)$`)
+	fenceLineRe    = regexp.MustCompile("^[ \t]*(`{3,}|~{3,})[ \t]*(.*)$")
+)
+
+// terminalStyle applies ANSI styles when color is enabled
+type terminalStyle struct {
+	color bool
+}
+
+func (s terminalStyle) apply(value string, colors ...text.Color) string {
+	if !s.color || value == "" {
+		return value
+	}
+
+	return text.Colors(colors).Sprint(value)
+}
+
+// Find the closing delimiter of an inline span, which can't directly follow whitespace
+func closingDelimiter(line string, start int, delim byte) int {
+	for i := start + 1; i < len(line); i++ {
+		if line[i] == delim && line[i-1] != ' ' && i > start+1 {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Render the inline djot syntax of a single line
+func (v *Vault) renderInline(path, line string, style terminalStyle) string {
+	var b strings.Builder
+
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; c {
+		case '\\':
+			if i+1 < len(line) {
+				i++
+				b.WriteByte(line[i])
+			} else {
+				b.WriteByte(c)
+			}
+		case '`':
+			if end := strings.IndexByte(line[i+1:], '`'); end >= 0 {
+				b.WriteString(style.apply(line[i+1:i+1+end], text.FgYellow))
+				i += end + 1
+			} else {
+				b.WriteByte(c)
+			}
+		case '[', '!':
+			m := anchoredLinkRe.FindStringSubmatch(line[i:])
+			if m == nil {
+				b.WriteByte(c)
+				continue
+			}
+
+			b.WriteString(v.renderLink(path, m[1], m[2], style))
+			i += len(m[0]) - 1
+		case '*', '_':
+			end := closingDelimiter(line, i, c)
+			if end < 0 || i+1 >= len(line) || line[i+1] == ' ' {
+				b.WriteByte(c)
+				continue
+			}
+
+			inner := v.renderInline(path, line[i+1:end], style)
+			if c == '*' {
+				b.WriteString(style.apply(inner, text.Bold))
+			} else {
+				b.WriteString(style.apply(inner, text.Italic))
+			}
+
+			i = end
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+// Links to notes show the title of the target and other links show the destination
+func (v *Vault) renderLink(path, label, dest string, style terminalStyle) string {
+	label = v.renderInline(path, label, style)
+
+	if target, ok := LinkTarget(path, dest); ok {
+		title := ""
+		if doc, err := ReadDocument(target); err == nil {
+			title = doc.Title()
+		}
+
+		switch {
+		case title == "":
+			return style.apply(label, text.Underline, text.FgBlue) + style.apply(" ("+v.RelPath(target)+")", text.Faint)
+		case label == "" || label == dest:
+			return style.apply(title, text.Underline, text.FgBlue)
+		default:
+			return style.apply(label, text.Underline, text.FgBlue) + style.apply(" ("+title+")", text.Faint)
+		}
+	}
+
+	if label == "" || label == dest {
+		return style.apply(dest, text.Underline, text.FgBlue)
+	}
+
+	return style.apply(label, text.Underline, text.FgBlue) + style.apply(" <"+dest+">", text.Faint)
+}
+
+// Render the header as a single line of `key: value` pairs
+func renderHeader(header Header, style terminalStyle) string {
+	fields := []string{}
+
+	for _, key := range header.Keys() {
+		value, _ := header.Get(key)
+		fields = append(fields, style.apply(key+":", text.Faint, text.Bold)+" "+style.apply(value, text.Faint))
+	}
+
+	return strings.Join(fields, style.apply(" · ", text.Faint))
+}
+
+// RenderTerminal formats the djot note for reading in a terminal, with ANSI styles when color is set
+//
+// Headings, emphasis, lists, task boxes, code blocks, and links are rendered. Note links show the title of the target
+func (v *Vault) RenderTerminal(path string, doc Document, color bool) string {
+	style := terminalStyle{color: color}
+
+	var b strings.Builder
+
+	if doc.Header.Len() > 0 {
+		b.WriteString(renderHeader(doc.Header, style) + "\n\n")
+	}
+
+	fence := ""
+
+	for line := range strings.SplitSeq(strings.TrimRight(strings.ReplaceAll(doc.Body, "\r\n", "\n"), "\n"), "\n") {
+		if m := fenceLineRe.FindStringSubmatch(line); m != nil {
+			switch {
+			case fence == "":
+				fence = m[1]
+				b.WriteString(style.apply("  ┌ "+m[2], text.Faint) + "\n")
+
+				continue
+			case m[1][0] == fence[0] && len(m[1]) >= len(fence) && m[2] == "":
+				fence = ""
+				b.WriteString(style.apply("  └", text.Faint) + "\n")
+
+				continue
+			}
+		}
+
+		if fence != "" {
+			b.WriteString(style.apply("  │ ", text.Faint) + style.apply(line, text.FgGreen) + "\n")
+			continue
+		}
+
+		b.WriteString(v.renderBlockLine(path, line, style) + "\n")
+	}
+
+	return b.String()
+}
+
+func (v *Vault) renderBlockLine(path, line string, style terminalStyle) string {
+	if m := headingRe.FindStringSubmatch(line); m != nil {
+		colors := []text.Color{text.Bold, text.FgCyan}
+		if len(m[1]) == 1 {
+			colors = append(colors, text.Underline)
+		}
+
+		return style.apply(v.renderInline(path, strings.TrimSpace(m[2]), style), colors...)
+	}
+
+	if quote, ok := strings.CutPrefix(line, ">"); ok {
+		return style.apply("│", text.Faint) + " " + style.apply(v.renderInline(path, strings.TrimSpace(quote), style), text.Italic)
+	}
+
+	if m := listItemRe.FindStringSubmatch(line); m != nil {
+		indent, marker, content := m[1], m[2], m[3]
+
+		if task := taskItemRe.FindStringSubmatch(line); task != nil {
+			if task[1] == " " {
+				return indent + "☐ " + v.renderInline(path, task[2], style)
+			}
+
+			return indent + style.apply("☑ ", text.FgGreen) + style.apply(v.renderInline(path, task[2], style), text.Faint)
+		}
+
+		if marker == "-" || marker == "*" || marker == "+" {
+			marker = "•"
+		}
+
+		return indent + style.apply(marker, text.FgCyan) + " " + v.renderInline(path, content, style)
+	}
+
+	return v.renderInline(path, line, style)
+}
@@ -0,0 +1,34 @@
+package notes
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Matches the counter that disambiguates notes with the same time name
+var timeNameSuffixRe = regexp.MustCompile(`^(.+Z)-\d+$`)
+
+func ToTimeName(t time.Time) string {
+	// Adapted from: https://stackoverflow.com/a/65221179/3219667
+	//  and https://pkg.go.dev/time
+	return strings.Replace(t.UTC().Format(time.RFC3339), ":", "_", 2) // or RFC9557?
+}
+
+// SuffixedTimeName disambiguates notes with the same time name, such as `2024-01-02T03_04_05Z-2`
+func SuffixedTimeName(t time.Time, n int) string {
+	return ToTimeName(t) + "-" + strconv.Itoa(n)
+}
+
+func FromTimeName(name string) (time.Time, error) {
+	parsedName := strings.Replace(timeNameSuffixRe.ReplaceAllString(name, "$1"), "_", ":", 2)
+	time, err := time.Parse(time.RFC3339, parsedName)
+
+	if err != nil {
+		return time, fmt.Errorf("failed to parse time %s (%s): %w", name, parsedName, err)
+	}
+
+	return time, nil
+}
@@ -0,0 +1,37 @@
+package notes
+
+import (
+	"strings"
+)
+
+const maxTitleLength = 80
+
+// Title is the first djot heading, then the `name` header, then the first non-empty line of the body
+func (d *Document) Title() string {
+	code := codeBlockRanges(d.Body)
+	offset := 0
+
+	for line := range strings.SplitAfterSeq(d.Body, "\n") {
+		if m := headingRe.FindStringSubmatch(strings.TrimRight(line, "\r\n")); m != nil && !inRanges(code, offset) {
+			if title := strings.TrimSpace(m[2]); title != "" {
+				return truncateTitle(title)
+			}
+		}
+
+		offset += len(line)
+	}
+
+	if name, _ := d.Header.Get(KeyName); strings.TrimSpace(name) != "" {
+		return truncateTitle(strings.TrimSpace(name))
+	}
+
+	return truncateTitle(firstLine(d.Body))
+}
+
+func truncateTitle(title string) string {
+	if runes := []rune(title); len(runes) > maxTitleLength {
+		return string(runes[:maxTitleLength-1]) + "…"
+	}
+
+	return title
+}
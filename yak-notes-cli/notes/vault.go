@@ -0,0 +1,313 @@
+// Package notes manages a vault of djot notes stored in subDirs of a sync directory
+package notes
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const NoteExt = ".dj"
+
+const maxCreateAttempts = 100
+
+var (
+	ErrUnknownSubDir = errors.New("unknown subDir")
+	ErrInvalidName   = errors.New("invalid note name")
+	ErrNoteNotFound  = errors.New("note not found")
+	ErrSameNote      = errors.New("source and target are the same note")
+)
+
+// Vault is the sync directory where each subDir ("Yak Pen") contains notes
+type Vault struct {
+	SyncDir string
+	// MigrationsDir is only required for operations that use the database index
+	MigrationsDir string
+}
+
+func NewVault(syncDir string) (*Vault, error) {
+	absDir, err := filepath.Abs(syncDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve sync directory %s: %w", syncDir, err)
+	}
+
+	return &Vault{SyncDir: absDir}, nil
+}
+
+// FileStat is the file system information for a single note
+type FileStat struct {
+	SubDir  string
+	Name    string
+	Path    string
+	ModTime time.Time
+}
+
+// Note is the content of a note, which is also the row stored in the database index
+type Note struct {
+	SubDir     string `db:"sub_dir"`
+	Filename   string `db:"filename"`
+	Title      string `db:"title"`
+	Content    string `db:"content"`
+	ModifiedAt string `db:"modified_at"`
+}
+
+func IsNoteFile(name string) bool {
+	return strings.HasSuffix(name, NoteExt)
+}
+
+func (v *Vault) ListSubDirs() (folderNames []string, err error) {
+	files, err := os.ReadDir(v.SyncDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sync directory %s: %w", v.SyncDir, err)
+	}
+
+	for _, file := range files {
+		if file.IsDir() && !(strings.HasPrefix(file.Name(), ".")) {
+			folderNames = append(folderNames, file.Name())
+		}
+	}
+
+	return
+}
+
+func (v *Vault) checkSubDir(subDir string) error {
+	folderNames, err := v.ListSubDirs()
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(folderNames, subDir) {
+		return fmt.Errorf(
+			"%w: '%s' is not one of %v subDirs in '%s'. Create the folder if intended",
+			ErrUnknownSubDir,
+			subDir,
+			folderNames,
+			v.SyncDir,
+		)
+	}
+
+	return nil
+}
+
+func (v *Vault) listSubDirNotes(subDir string) (stats []FileStat, err error) {
+	dir := filepath.Join(v.SyncDir, subDir)
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", dir, err)
+	}
+
+	for _, file := range files {
+		if !file.IsDir() && IsNoteFile(file.Name()) {
+			fi, err := file.Info()
+			if err != nil {
+				return stats, fmt.Errorf("error with specified file (`%v`): %w", file, err)
+			}
+
+			stats = append(stats, FileStat{
+				SubDir:  subDir,
+				Name:    file.Name(),
+				Path:    filepath.Join(dir, file.Name()),
+				ModTime: fi.ModTime(),
+			})
+		}
+	}
+
+	return
+}
+
+// ListNotes returns the notes in subDir or in all subDirs when subDir is empty
+func (v *Vault) ListNotes(subDir string) (stats []FileStat, err error) {
+	folderNames := []string{subDir}
+	if subDir == "" {
+		if folderNames, err = v.ListSubDirs(); err != nil {
+			return
+		}
+	}
+
+	for _, name := range folderNames {
+		subStats, err := v.listSubDirNotes(name)
+		if err != nil {
+			return stats, err
+		}
+
+		stats = append(stats, subStats...)
+	}
+
+	return
+}
+
+// ReadNote reads a note in one of the subDirs, where hidden names are rejected so that trashed, archived, and template
+// files can't be read
+func (v *Vault) ReadNote(subDir, filename string) (Note, error) {
+	for _, name := range []string{subDir, filename} {
+		if filepath.Base(name) != name || strings.HasPrefix(name, ".") {
+			return Note{}, fmt.Errorf("%w: %s/%s", ErrInvalidName, subDir, filename)
+		}
+	}
+
+	if err := v.checkSubDir(subDir); err != nil {
+		return Note{}, err
+	}
+
+	path := filepath.Join(v.SyncDir, subDir, filename)
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return Note{}, fmt.Errorf("failed to get file info for %s: %w", path, err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Note{}, fmt.Errorf("failed to read file %s: %w", path, err)
+	}
+
+	doc := ParseDocument(string(content))
+
+	return Note{
+		SubDir:     subDir,
+		Filename:   filename,
+		Title:      doc.Title(),
+		Content:    string(content),
+		ModifiedAt: fi.ModTime().Format(time.RFC3339),
+	}, nil
+}
+
+func CreateFile(path string) (err error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	defer file.Close()
+
+	return
+}
+
+// CreateNote creates an empty note named by the current time and returns the path
+func (v *Vault) CreateNote(subDir string) (string, error) {
+	return v.CreateNoteWithContent(subDir, time.Now(), "")
+}
+
+// CreateNoteWithContent creates a note named by the creation time without overwriting existing files
+//
+// Notes created within the same second, such as from a script or another device, get a suffixed name
+func (v *Vault) CreateNoteWithContent(subDir string, created time.Time, content string) (string, error) {
+	if err := v.checkSubDir(subDir); err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(v.SyncDir, subDir)
+	taken := map[string]bool{}
+
+	for range maxCreateAttempts {
+		path := availableTimeName(dir, created, NoteExt, taken)
+
+		err := writeFileExclusive(path, []byte(content))
+		if err == nil {
+			return path, nil
+		} else if !errors.Is(err, os.ErrExist) {
+			return "", err
+		}
+
+		// Another process created the same name after it was checked
+		taken[path] = true
+	}
+
+	return "", fmt.Errorf("failed to find an available name for a note in %s after %d attempts", dir, maxCreateAttempts)
+}
+
+// RelPath returns the vault-relative `subDir/filename` reference for a note path
+func (v *Vault) RelPath(path string) string {
+	rel, err := filepath.Rel(v.SyncDir, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// AbsPath converts a vault-relative `subDir/filename` reference to a file path
+func (v *Vault) AbsPath(ref string) string {
+	if filepath.IsAbs(ref) {
+		return ref
+	}
+
+	return filepath.Join(v.SyncDir, filepath.FromSlash(ref))
+}
+
+func (v *Vault) statNote(path string) (FileStat, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return FileStat{}, fmt.Errorf("failed to get file info for %s: %w", path, err)
+	}
+
+	subDir := ""
+	if rel := v.RelPath(path); rel != path {
+		subDir = filepath.Dir(filepath.FromSlash(rel))
+	}
+
+	return FileStat{SubDir: subDir, Name: fi.Name(), Path: path, ModTime: fi.ModTime()}, nil
+}
+
+// Resolve finds a note from a file path, a vault-relative `subDir/filename`, or a bare filename
+func (v *Vault) Resolve(ref string) (FileStat, error) {
+	if ref == "" {
+		return FileStat{}, fmt.Errorf("%w: empty reference", ErrInvalidName)
+	}
+
+	name := ref
+	if !IsNoteFile(name) {
+		name += NoteExt
+	}
+
+	candidates := []string{ref, filepath.Join(v.SyncDir, filepath.FromSlash(name))}
+	for _, candidate := range candidates {
+		if fi, err := os.Stat(candidate); err == nil && !fi.IsDir() {
+			absPath, err := filepath.Abs(candidate)
+			if err != nil {
+				return FileStat{}, fmt.Errorf("failed to resolve %s: %w", candidate, err)
+			}
+
+			return v.statNote(absPath)
+		}
+	}
+
+	if filepath.Base(name) == name {
+		stats, err := v.ListNotes("")
+		if err != nil {
+			return FileStat{}, err
+		}
+
+		for _, stat := range stats {
+			if stat.Name == name {
+				return stat, nil
+			}
+		}
+	}
+
+	return FileStat{}, fmt.Errorf("%w: %s", ErrNoteNotFound, ref)
+}
+
+// RecentNotes returns up to limit notes across all subDirs, most recently modified first
+func (v *Vault) RecentNotes(limit int) ([]FileStat, error) {
+	stats, err := v.ListNotes("")
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].ModTime.After(stats[j].ModTime)
+	})
+
+	if limit > 0 && len(stats) > limit {
+		stats = stats[:limit]
+	}
+
+	return stats, nil
+}
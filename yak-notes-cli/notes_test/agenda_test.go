@@ -0,0 +1,54 @@
+package notes_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgenda(t *testing.T) {
+	vault := initTestVault(t, "work", "personal")
+	writeTestNote(t, vault, "work", "2024-01-01T00_00_00Z.dj", ": state=queue\\\n: soft-deadline=2024-05-09\\\n")
+	writeTestNote(t, vault, "work", "2024-01-02T00_00_00Z.dj", ": state=in-progress\\\n: hard-deadline=2024-05-10\\\n")
+	writeTestNote(t, vault, "personal", "2024-01-03T00_00_00Z.dj", ": state=backlog\\\n: start-date=2024-05-01\\\n")
+	writeTestNote(t, vault, "personal", "2024-01-04T00_00_00Z.dj", ": state=queue\\\n: start-date=2024-05-12\\\n")
+	writeTestNote(t, vault, "work", "2024-01-05T00_00_00Z.dj", ": state=queue\\\n: hard-deadline=2024-06-30\\\n")
+	writeTestNote(t, vault, "work", "2024-01-06T00_00_00Z.dj", ": state=complete\\\n: hard-deadline=2024-05-01\\\n")
+	writeTestNote(t, vault, "work", "2024-01-07T00_00_00Z.dj", ": state=queue\\\n")
+	writeTestNote(t, vault, "work", "2024-01-08T00_00_00Z.dj", ": state=queue\\\n: hard-deadline=soon\\\n")
+	writeTestNote(t, vault, "work", "2024-01-09T00_00_00Z.dj", ": state=todo\\\n: hard-deadline=2024-05-09\\\n")
+
+	items, warnings, err := vault.Agenda(time.Date(2024, 5, 10, 15, 0, 0, 0, time.Local), 7)
+	require.NoError(t, err)
+	require.Len(t, warnings, 2)
+	assert.Contains(t, warnings[0], "skipped work/2024-01-08T00_00_00Z.dj: invalid schedule")
+	assert.Contains(t, warnings[1], "skipped work/2024-01-09T00_00_00Z.dj: invalid state")
+
+	summary := [][2]string{}
+	for _, item := range items {
+		summary = append(summary, [2]string{string(item.Category), vault.RelPath(item.Stat.Path)})
+	}
+
+	assert.Equal(t, [][2]string{
+		{string(notes.AgendaOverdue), "work/2024-01-01T00_00_00Z.dj"},
+		{string(notes.AgendaDueToday), "work/2024-01-02T00_00_00Z.dj"},
+		{string(notes.AgendaStartable), "personal/2024-01-03T00_00_00Z.dj"},
+		{string(notes.AgendaUpcoming), "personal/2024-01-04T00_00_00Z.dj"},
+	}, summary)
+}
+
+func TestScheduleDeadline(t *testing.T) {
+	doc := notes.ParseDocument(": soft-deadline=2024-05-09\\\n: hard-deadline=2024-05-01T12:00:00Z\\\n")
+
+	schedule, err := doc.Header.Schedule()
+	require.NoError(t, err)
+	assert.True(t, schedule.Start.IsZero())
+	assert.Equal(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), schedule.Deadline())
+
+	doc = notes.ParseDocument(": start-date=soon\\\n")
+	_, err = doc.Header.Schedule()
+	require.Error(t, err)
+}
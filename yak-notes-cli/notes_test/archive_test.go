@@ -0,0 +1,125 @@
+package notes_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveRestore(t *testing.T) {
+	vault := initTestVault(t, "work")
+	path := writeTestNote(t, vault, "work", "2024-01-01T00_00_00Z.dj", ": state=queue\\\n\nBody\n")
+
+	archived, err := vault.Archive(path)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(vault.SyncDir, notes.ArchiveDir, "work", "2024-01-01T00_00_00Z.dj"), archived)
+
+	_, err = os.Stat(path)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	stats, err := vault.ListNotes("")
+	require.NoError(t, err)
+	assert.Empty(t, stats)
+
+	doc, err := notes.ReadDocument(archived)
+	require.NoError(t, err)
+	assert.Equal(t, []string{notes.KeyState, notes.KeyArchivedAt}, doc.Header.Keys())
+
+	stat, err := vault.ResolveRemoved("work/2024-01-01T00_00_00Z")
+	require.NoError(t, err)
+	assert.Equal(t, archived, stat.Path)
+
+	restored, err := vault.Restore(stat.Path)
+	require.NoError(t, err)
+	assert.Equal(t, path, restored)
+
+	content, err := os.ReadFile(restored)
+	require.NoError(t, err)
+	assert.Equal(t, ": state=queue\\\n\nBody\n", string(content))
+
+	_, err = vault.Restore(restored)
+	require.ErrorIs(t, err, notes.ErrNotRemoved)
+}
+
+func TestPurgeTrash(t *testing.T) {
+	vault := initTestVault(t, "work")
+	oldPath := writeTestNote(t, vault, "work", "2024-01-01T00_00_00Z.dj", "Old\n")
+	newPath := writeTestNote(t, vault, "work", "2024-01-02T00_00_00Z.dj", "New\n")
+
+	oldTrashed, err := vault.Trash(oldPath)
+	require.NoError(t, err)
+
+	newTrashed, err := vault.Trash(newPath)
+	require.NoError(t, err)
+
+	stats, err := vault.ListRemoved(notes.TrashDir)
+	require.NoError(t, err)
+	assert.Len(t, stats, 2)
+
+	// Backdate one note in the trash
+	doc, err := notes.ReadDocument(oldTrashed)
+	require.NoError(t, err)
+	doc.Header.SetTime(notes.KeyTrashedAt, time.Now().AddDate(0, 0, -31))
+	require.NoError(t, notes.WriteDocument(oldTrashed, doc))
+
+	purged, err := vault.PurgeTrash(time.Now(), 30*24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []string{oldTrashed}, purged)
+
+	_, err = os.Stat(newTrashed)
+	require.NoError(t, err)
+}
+
+func TestPurgeTrashUnlinks(t *testing.T) {
+	vault := initTestVault(t, "work", "personal")
+	path := writeTestNote(t, vault, "work", "2024-01-01T00_00_00Z.dj", "Purged\n")
+	sourcePath := writeTestNote(t, vault, "personal", "2024-01-02T00_00_00Z.dj",
+		": links=work/2024-01-01T00_00_00Z.dj,work/2024-01-03T00_00_00Z.dj\\\n\n"+
+			"See [the note](../work/2024-01-01T00_00_00Z.dj#intro) and <../work/2024-01-01T00_00_00Z.dj>\n\n"+
+			"[ref]: ../work/2024-01-01T00_00_00Z.dj\n")
+
+	trashed, err := vault.Trash(path)
+	require.NoError(t, err)
+
+	sources, err := vault.LinkSources(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"personal/2024-01-02T00_00_00Z.dj"}, sources)
+
+	purged, err := vault.PurgeTrash(time.Now(), 0)
+	require.NoError(t, err)
+
+	plan, err := vault.PlanPurgedUnlinks(purged)
+	require.NoError(t, err)
+	require.Len(t, plan, 1)
+	require.NoError(t, vault.Apply(plan))
+
+	assert.Equal(t, []string{trashed}, purged)
+
+	content, err := os.ReadFile(sourcePath)
+	require.NoError(t, err)
+	assert.Equal(t,
+		": links=work/2024-01-03T00_00_00Z.dj\\\n\nSee the note and ../work/2024-01-01T00_00_00Z.dj\n\n",
+		string(content),
+	)
+}
+
+func TestRestoreConflict(t *testing.T) {
+	vault := initTestVault(t, "work")
+	path := writeTestNote(t, vault, "work", "2024-01-01T00_00_00Z.dj", "Original\n")
+
+	trashed, err := vault.Trash(path)
+	require.NoError(t, err)
+
+	writeTestNote(t, vault, "work", "2024-01-01T00_00_00Z.dj", "Replacement\n")
+
+	_, err = vault.Restore(trashed)
+	require.ErrorIs(t, err, os.ErrExist)
+
+	_, err = os.Stat(trashed)
+	require.NoError(t, err, "a failed restore must keep the trashed note")
+}
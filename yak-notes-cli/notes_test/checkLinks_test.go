@@ -0,0 +1,43 @@
+package notes_test
+
+import (
+	"testing"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckLinks(t *testing.T) {
+	vault := initTestVault(t, "work")
+	sourcePath := writeTestNote(t, vault, "work", "2024-01-01T00_00_00Z.dj",
+		": links=work/2024-02-01T00_00_00Z.dj\\\n\n[merged](2024-02-01T00_00_00Z.dj) [split](2024-03-01T00_00_00Z.dj) [gone](missing.dj)\n")
+	// Merge history is carried forward, so 2024-02 was merged into 2024-04 and then into 2024-06
+	writeTestNote(t, vault, "work", "2024-06-01T00_00_00Z.dj",
+		": merged-from=work/2024-04-01T00_00_00Z.dj,work/2024-02-01T00_00_00Z.dj\\\n")
+	writeTestNote(t, vault, "work", "2024-07-01T00_00_00Z.dj", ": split-from=work/2024-03-01T00_00_00Z.dj\\\n")
+	writeTestNote(t, vault, "work", "2024-08-01T00_00_00Z.dj", ": split-from=work/2024-03-01T00_00_00Z.dj\\\n")
+
+	broken, err := vault.CheckLinks()
+	require.NoError(t, err)
+	require.Len(t, broken, 3)
+
+	fix, ok := broken[0].Fix()
+	assert.True(t, ok)
+	assert.Equal(t, "work/2024-06-01T00_00_00Z.dj", fix)
+	assert.Equal(t, []string{"work/2024-07-01T00_00_00Z.dj", "work/2024-08-01T00_00_00Z.dj"}, broken[1].Candidates)
+	assert.Empty(t, broken[2].Candidates)
+
+	plan, err := vault.PlanLinkFixes(broken)
+	require.NoError(t, err)
+	require.NoError(t, vault.Apply(plan))
+
+	doc, err := notes.ReadDocument(sourcePath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"work/2024-06-01T00_00_00Z.dj"}, doc.Header.GetList(notes.KeyLinks))
+	assert.Contains(t, doc.Body, "[merged](2024-06-01T00_00_00Z.dj)")
+
+	broken, err = vault.CheckLinks()
+	require.NoError(t, err)
+	assert.Len(t, broken, 2)
+}
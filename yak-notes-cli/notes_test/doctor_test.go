@@ -0,0 +1,152 @@
+package notes_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findingsByPath(findings []notes.Finding) map[string][]string {
+	byPath := map[string][]string{}
+	for _, finding := range findings {
+		byPath[finding.Path] = append(byPath[finding.Path], string(finding.Severity)+": "+finding.Message)
+	}
+
+	return byPath
+}
+
+func TestDoctorFiles(t *testing.T) {
+	vault := initTestVault(t, "notes", "other")
+	writeTestNote(t, vault, "notes", "2024-01-01T00_00_00Z.dj", "Body\n")
+	writeTestNote(t, vault, "notes", "draft.dj", ": creation_date=2024-02-01T00:00:00Z\\\n\n[link](draft.dj)\n")
+	writeTestNote(t, vault, "notes", "image.png", "")
+	writeTestNote(t, vault, "notes", ".2024-01-01T00_00_00Z.dj.tmp123", "")
+	writeTestNote(t, vault, "notes", ".hidden", "")
+	writeTestNote(t, vault, "other", "2024-01-01T00_00_00Z-1.dj", "Body\n")
+
+	findings, err := vault.Doctor()
+	require.NoError(t, err)
+
+	byPath := findingsByPath(findings)
+	assert.Equal(t, []string{"warning: the name is not a creation timestamp"}, byPath["notes/draft.dj"])
+	assert.Equal(t, []string{"warning: not a .dj note"}, byPath["notes/image.png"])
+	assert.Equal(t,
+		[]string{"warning: temporary file left by an interrupted write"},
+		byPath["notes/.2024-01-01T00_00_00Z.dj.tmp123"],
+	)
+	assert.NotContains(t, byPath, "notes/.hidden")
+	assert.Equal(t,
+		[]string{"info: the creation time is shared with other/2024-01-01T00_00_00Z-1.dj"},
+		byPath["notes/2024-01-01T00_00_00Z.dj"],
+	)
+	assert.Equal(t, []string{"info: there is no database index to check"}, byPath["yak-shears.db"])
+
+	fixed, err := vault.FixFindings(findings)
+	require.NoError(t, err)
+	assert.Len(t, fixed, 2)
+
+	assert.NoFileExists(t, filepath.Join(vault.SyncDir, "notes", ".2024-01-01T00_00_00Z.dj.tmp123"))
+
+	content, err := os.ReadFile(filepath.Join(vault.SyncDir, "notes", "2024-02-01T00_00_00Z.dj"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "[link](2024-02-01T00_00_00Z.dj)")
+}
+
+func TestDoctorHeaders(t *testing.T) {
+	vault := initTestVault(t, "notes")
+	whitespacePath := writeTestNote(t, vault, "notes", "2024-01-01T00_00_00Z.dj",
+		": state=backlog\\\n: start-date=2024-01-02\\  \n: links=a.dj\\\n\nBody\n")
+	writeTestNote(t, vault, "notes", "2024-01-02T00_00_00Z.dj", ": state=backlog\\\n: bad key=1\\\n\nBody\n")
+	writeTestNote(t, vault, "notes", "2024-01-03T00_00_00Z.dj", ": state=unknown\\\n: hard-deadline=soon\\\n\nBody\n")
+	writeTestNote(t, vault, "notes", "2024-01-04T00_00_00Z.dj", "Body\n")
+	writeTestNote(t, vault, "notes", "2024-01-04T00_00_00Z-1.dj", "Body\n")
+
+	findings, err := vault.Doctor()
+	require.NoError(t, err)
+
+	byPath := findingsByPath(findings)
+	assert.Equal(t,
+		[]string{"error: header line 2 has trailing whitespace after the '\\'"},
+		byPath["notes/2024-01-01T00_00_00Z.dj"],
+	)
+	assert.Equal(t,
+		[]string{"error: line 2 is not a header line in the `: key=value\\` format"},
+		byPath["notes/2024-01-02T00_00_00Z.dj"],
+	)
+	assert.Len(t, byPath["notes/2024-01-03T00_00_00Z.dj"], 2)
+	assert.Equal(t, notes.SeverityError, findings[0].Severity)
+	assert.Equal(t, notes.SeverityInfo, findings[len(findings)-1].Severity)
+
+	_, err = vault.FixFindings(findings)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(whitespacePath)
+	require.NoError(t, err)
+	assert.Equal(t, ": state=backlog\\\n: start-date=2024-01-02\\\n: links=a.dj\\\n\nBody\n", string(content))
+}
+
+func TestDoctorIndex(t *testing.T) {
+	vault := initTestVault(t, "notes")
+	writeTestNote(t, vault, "notes", "2024-01-01T00_00_00Z.dj", "Kept\n")
+	removedPath := writeTestNote(t, vault, "notes", "2024-01-02T00_00_00Z.dj", "Removed\n")
+
+	db, err := vault.OpenIndex()
+	require.NoError(t, err)
+	require.NoError(t, vault.Reindex(db))
+	require.NoError(t, db.Close())
+
+	require.NoError(t, os.Remove(removedPath))
+	writeTestNote(t, vault, "notes", "2024-01-03T00_00_00Z.dj", "Added\n")
+
+	findings, err := vault.Doctor()
+	require.NoError(t, err)
+
+	byPath := findingsByPath(findings)
+	assert.Equal(t, []string{"warning: the indexed note no longer exists"}, byPath["notes/2024-01-02T00_00_00Z.dj"])
+	assert.Equal(t, []string{"warning: the note is missing from the index"}, byPath["notes/2024-01-03T00_00_00Z.dj"])
+	assert.NotContains(t, byPath, "notes/2024-01-01T00_00_00Z.dj")
+
+	fixed, err := vault.FixFindings(findings)
+	require.NoError(t, err)
+	assert.Len(t, fixed, 2)
+
+	findings, err = vault.Doctor()
+	require.NoError(t, err)
+	assert.Empty(t, findings)
+}
+
+func TestDoctorIndexDuplicateName(t *testing.T) {
+	vault := initTestVault(t, "notes", "other")
+	writeTestNote(t, vault, "notes", "2024-01-01T00_00_00Z.dj", "Kept\n")
+
+	db, err := vault.OpenIndex()
+	require.NoError(t, err)
+	require.NoError(t, vault.Reindex(db))
+	require.NoError(t, db.Close())
+
+	writeTestNote(t, vault, "other", "2024-01-01T00_00_00Z.dj", "Copied\n")
+
+	findings, err := vault.Doctor()
+	require.NoError(t, err)
+
+	byPath := findingsByPath(findings)
+	assert.Equal(t,
+		[]string{
+			"error: the name is also used in another subDir, which conflicts in the index: notes/2024-01-01T00_00_00Z.dj",
+			"warning: the note is missing from the index, but the index can't be rebuilt until the names used in two subDirs are resolved",
+		},
+		byPath["other/2024-01-01T00_00_00Z.dj"],
+	)
+
+	for _, finding := range findings {
+		assert.Empty(t, finding.Fix, finding.String())
+	}
+
+	fixed, err := vault.FixFindings(findings)
+	require.NoError(t, err)
+	assert.Empty(t, fixed)
+}
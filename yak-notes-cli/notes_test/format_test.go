@@ -0,0 +1,105 @@
+package notes_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatNote(t *testing.T) {
+	parameters := []struct {
+		content  string
+		expected string
+		issues   []string
+	}{
+		{
+			content:  "",
+			expected: "",
+			issues:   []string{},
+		},
+		{
+			content:  ": state=queue\\\n\n# Title\n\n- item\n",
+			expected: ": state=queue\\\n\n# Title\n\n- item\n",
+			issues:   []string{},
+		},
+		{
+			content:  ": state=queue\\  \nBody  \n\n\n",
+			expected: ": state=queue\\\n\nBody\n",
+			issues: []string{
+				"1: trailing whitespace",
+				"2: trailing whitespace",
+				"2: missing blank line after the header",
+				"4: extra blank line",
+				"4: blank lines at the end of the file",
+			},
+		},
+		{
+			content:  "\n#   Title\n\ntext\n\n```\n# code  \n```\n## Sub",
+			expected: "# Title\n\ntext\n\n```\n# code  \n```\n\n## Sub\n",
+			issues: []string{
+				"9: missing newline at the end of the file",
+				"1: blank line at the start of the file",
+				"2: use one space after the heading marker",
+				"9: missing blank line before the heading",
+			},
+		},
+		{
+			content:  "* one\n  + nested\n\n> * [ ] quoted\n",
+			expected: "- one\n  + nested\n\n> - [ ] quoted\n",
+			issues: []string{
+				"1: use '-' instead of '*' for the bullet",
+				"4: use '-' instead of '*' for the bullet",
+			},
+		},
+		{
+			content:  "* one\n\n+ two\n",
+			expected: "* one\n\n+ two\n",
+			issues:   []string{},
+		},
+		{
+			content:  "Windows\r\n\r\n\r\n* item\r\n",
+			expected: "Windows\r\n\r\n- item\r\n",
+			issues:   []string{"3: extra blank line", "4: use '-' instead of '*' for the bullet"},
+		},
+	}
+
+	for _, p := range parameters {
+		formatted, issues := notes.FormatNote(p.content)
+		assert.Equal(t, p.expected, formatted, p.content)
+
+		messages := []string{}
+		for _, issue := range issues {
+			messages = append(messages, issue.String())
+		}
+
+		assert.Equal(t, p.issues, messages, p.content)
+
+		reformatted, issues := notes.FormatNote(formatted)
+		assert.Equal(t, formatted, reformatted)
+		assert.Empty(t, issues)
+	}
+}
+
+func TestFormatFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "note.dj")
+	require.NoError(t, os.WriteFile(path, []byte("* item  \n"), 0o600))
+
+	issues, err := notes.FormatFile(path, true)
+	require.NoError(t, err)
+	assert.Len(t, issues, 2)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "* item  \n", string(content))
+
+	_, err = notes.FormatFile(path, false)
+	require.NoError(t, err)
+
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "- item\n", string(content))
+}
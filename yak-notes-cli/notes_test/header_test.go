@@ -0,0 +1,99 @@
+package notes_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const importedNote = `: id=x-coredata://ABC/ICNote/p1\
+: creation_date=2024-03-04T05:06:07.123456\
+: name=Groceries, etc.\
+: folder=Notes\
+
+# Groceries
+
+- [ ] milk
+`
+
+func TestParseDocumentRoundTrip(t *testing.T) {
+	parameters := []string{
+		"",
+		"No header\n\n: not=header\\\n",
+		importedNote,
+		": state=queue\\\n",
+		": state=queue\\\nBody without a blank line\n",
+		": state=queue\\\r\n\r\nWindows line endings\r\n",
+		": state=queue\\\n\n",
+	}
+
+	for _, content := range parameters {
+		doc := notes.ParseDocument(content)
+		assert.Equal(t, content, doc.String())
+	}
+}
+
+func TestParseDocumentFields(t *testing.T) {
+	doc := notes.ParseDocument(importedNote)
+
+	assert.Equal(t, []string{"id", "creation_date", "name", "folder"}, doc.Header.Keys())
+	assert.Equal(t, "# Groceries\n\n- [ ] milk\n", doc.Body)
+
+	name, ok := doc.Header.Get("name")
+	assert.True(t, ok)
+	assert.Equal(t, "Groceries, etc.", name)
+
+	created, ok, err := doc.Header.GetTime(notes.KeyCreationDate)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2024, 3, 4, 5, 6, 7, 123456000, time.UTC), created)
+}
+
+func TestHeaderLists(t *testing.T) {
+	doc := notes.ParseDocument("Body\n")
+
+	doc.Header.AppendList(notes.KeyMergedFrom, "work/a.dj", "work/b.dj")
+	doc.Header.AppendList(notes.KeyMergedFrom, "work/b.dj", "work/c.dj")
+
+	assert.Equal(t, []string{"work/a.dj", "work/b.dj", "work/c.dj"}, doc.Header.GetList(notes.KeyMergedFrom))
+	assert.Equal(t, ": merged-from=work/a.dj,work/b.dj,work/c.dj\\\n\nBody\n", doc.String())
+
+	doc.Header.SetList(notes.KeyMergedFrom, nil)
+	assert.Equal(t, "Body\n", doc.String())
+}
+
+func TestHeaderState(t *testing.T) {
+	doc := notes.ParseDocument(": state=unknown\\\n")
+
+	_, err := doc.Header.State()
+	require.ErrorIs(t, err, notes.ErrInvalidState)
+
+	doc.Header.SetState(notes.StateInProgress)
+	state, err := doc.Header.State()
+	require.NoError(t, err)
+	assert.Equal(t, notes.StateInProgress, state)
+}
+
+func TestWriteDocument(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "note.dj")
+	require.NoError(t, os.WriteFile(path, []byte(importedNote), 0o600))
+
+	doc, err := notes.ReadDocument(path)
+	require.NoError(t, err)
+
+	doc.Header.Set("folder", "Archive")
+	require.NoError(t, notes.WriteDocument(path, doc))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), ": folder=Archive\\\n\n# Groceries")
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
@@ -0,0 +1,53 @@
+package notes_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenJournal(t *testing.T) {
+	vault := initTestVault(t, "journal")
+	writeTestTemplate(t, vault, "journal", "# {{.Date}}\n")
+	previous := writeTestNote(t, vault, "journal", "2024-05-08T07_00_00Z.dj",
+		": journal-date=2024-05-08\\\n\n- [x] Done\n- [ ] Unfinished\n  - [ ] Nested\n")
+	writeTestNote(t, vault, "journal", "2024-05-06T07_00_00Z.dj", ": journal-date=2024-05-06\\\n\n- [ ] Older\n")
+
+	path, created, err := vault.OpenJournal("journal", time.Date(2024, 5, 10, 9, 0, 0, 0, time.Local))
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	doc, err := notes.ReadDocument(path)
+	require.NoError(t, err)
+
+	date, _ := doc.Header.Get(notes.KeyJournalDate)
+	assert.Equal(t, "2024-05-10", date)
+
+	prev, _ := doc.Header.Get(notes.KeyPreviousJournal)
+	assert.Equal(t, vault.RelPath(previous), prev)
+	assert.Equal(t, "# 2024-05-10\n\n## Carried over\n\n- [ ] Unfinished\n- [ ] Nested\n", doc.Body)
+
+	again, created, err := vault.OpenJournal("journal", time.Date(2024, 5, 10, 18, 0, 0, 0, time.Local))
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, path, again)
+}
+
+func TestOpenJournalFirst(t *testing.T) {
+	vault := initTestVault(t, "journal")
+
+	path, created, err := vault.OpenJournal("journal", time.Date(2024, 5, 10, 9, 0, 0, 0, time.Local))
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	doc, err := notes.ReadDocument(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{notes.KeyJournalDate}, doc.Header.Keys())
+	assert.Empty(t, doc.Body)
+
+	_, _, err = vault.OpenJournal("missing", time.Now())
+	require.ErrorIs(t, err, notes.ErrUnknownSubDir)
+}
@@ -0,0 +1,77 @@
+package notes_test
+
+import (
+	"testing"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const linkedBody = "See [the source](b.dj#intro), <../personal/c.dj>, and [docs](https://djot.net).\n" +
+	"\n" +
+	"```\n[skipped](d.dj)\n```\n" +
+	"\n" +
+	"[ref]: b.dj\n"
+
+func TestParseLinks(t *testing.T) {
+	dests := []string{}
+	for _, link := range notes.ParseLinks(linkedBody) {
+		dests = append(dests, link.Dest)
+	}
+
+	assert.Equal(t, []string{"b.dj#intro", "../personal/c.dj", "https://djot.net", "b.dj"}, dests)
+}
+
+func TestLinkTarget(t *testing.T) {
+	target, ok := notes.LinkTarget("/vault/work/a.dj", "../personal/c.dj#top")
+	assert.True(t, ok)
+	assert.Equal(t, "/vault/personal/c.dj", target)
+
+	_, ok = notes.LinkTarget("/vault/work/a.dj", "https://example.com/c.dj")
+	assert.False(t, ok)
+}
+
+func TestRewriteLinks(t *testing.T) {
+	content, count := notes.RewriteLinks("/vault/work/a.dj", linkedBody, func(target string) (string, bool) {
+		if target == "/vault/work/b.dj" {
+			return "/vault/personal/z.dj", true
+		}
+
+		return "", false
+	})
+
+	assert.Equal(t, 2, count)
+	assert.Contains(t, content, "[the source](../personal/z.dj#intro)")
+	assert.Contains(t, content, "[ref]: ../personal/z.dj\n")
+	assert.Contains(t, content, "[skipped](d.dj)")
+}
+
+func TestRelocateLinks(t *testing.T) {
+	content := notes.RelocateLinks("/vault/work/a.dj", "/vault/personal/x.dj", "[b](b.dj) and [c](../personal/c.dj)")
+
+	assert.Equal(t, "[b](../work/b.dj) and [c](c.dj)", content)
+}
+
+func TestAddLinkAndBacklinks(t *testing.T) {
+	vault := initTestVault(t, "work", "personal")
+	fromPath := writeTestNote(t, vault, "work", "2024-01-01T00_00_00Z.dj", "From\n")
+	toPath := writeTestNote(t, vault, "personal", "2024-02-01T00_00_00Z.dj", "To\n")
+	writeTestNote(t, vault, "work", "2024-03-01T00_00_00Z.dj", "See [to](../personal/2024-02-01T00_00_00Z.dj)\n")
+
+	added, err := vault.AddLink(fromPath, toPath)
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	added, err = vault.AddLink(fromPath, toPath)
+	require.NoError(t, err)
+	assert.False(t, added)
+
+	doc, err := notes.ReadDocument(fromPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"personal/2024-02-01T00_00_00Z.dj"}, doc.Header.GetList(notes.KeyLinks))
+
+	sources, err := vault.Backlinks(toPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"work/2024-01-01T00_00_00Z.dj", "work/2024-03-01T00_00_00Z.dj"}, sources)
+}
@@ -0,0 +1,52 @@
+package notes_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanMerge(t *testing.T) {
+	vault := initTestVault(t, "work", "personal")
+	fromPath := writeTestNote(t, vault, "work", "2024-01-01T00_00_00Z.dj", "Source with [peer](2024-03-01T00_00_00Z.dj)\n")
+	toPath := writeTestNote(t, vault, "personal", "2024-02-01T00_00_00Z.dj", ": state=queue\\\n\nTarget\n")
+	peerPath := writeTestNote(
+		t, vault, "work", "2024-03-01T00_00_00Z.dj",
+		": links=work/2024-01-01T00_00_00Z.dj\\\n\nSee [source](2024-01-01T00_00_00Z.dj)\n",
+	)
+
+	plan, err := vault.PlanMerge(fromPath, toPath)
+	require.NoError(t, err)
+	require.Len(t, plan, 3)
+	assert.Contains(t, vault.FormatPlan(plan), "delete work/2024-01-01T00_00_00Z.dj")
+
+	_, err = os.Stat(fromPath)
+	require.NoError(t, err, "planning must not modify files")
+
+	require.NoError(t, vault.Apply(plan))
+
+	_, err = os.Stat(fromPath)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	target, err := notes.ReadDocument(toPath)
+	require.NoError(t, err)
+	assert.Equal(t, "Target\n\nSource with [peer](../work/2024-03-01T00_00_00Z.dj)\n", target.Body)
+	assert.Equal(t, []string{"work/2024-01-01T00_00_00Z.dj"}, target.Header.GetList(notes.KeyMergedFrom))
+
+	peer, err := notes.ReadDocument(peerPath)
+	require.NoError(t, err)
+	assert.Equal(t, "See [source](../personal/2024-02-01T00_00_00Z.dj)\n", peer.Body)
+	assert.Equal(t, []string{"personal/2024-02-01T00_00_00Z.dj"}, peer.Header.GetList(notes.KeyLinks))
+}
+
+func TestPlanMergeSameNote(t *testing.T) {
+	vault := initTestVault(t, "work")
+	path := writeTestNote(t, vault, "work", "2024-01-01T00_00_00Z.dj", "")
+
+	_, err := vault.PlanMerge(path, path)
+
+	require.ErrorIs(t, err, notes.ErrSameNote)
+}
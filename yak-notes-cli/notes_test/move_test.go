@@ -0,0 +1,69 @@
+package notes_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanMove(t *testing.T) {
+	vault := initTestVault(t, "work", "personal")
+	path := writeTestNote(t, vault, "work", "2024-01-01T00_00_00Z.dj", "See [peer](2024-03-01T00_00_00Z.dj)\n")
+	peerPath := writeTestNote(
+		t, vault, "work", "2024-03-01T00_00_00Z.dj",
+		": links=work/2024-01-01T00_00_00Z.dj\\\n\nSee [moved](2024-01-01T00_00_00Z.dj#intro)\n",
+	)
+
+	plan, err := vault.PlanMove(path, "personal")
+	require.NoError(t, err)
+	require.Len(t, plan, 3)
+	assert.Contains(t, vault.FormatPlan(plan), "create personal/2024-01-01T00_00_00Z.dj")
+
+	require.NoError(t, vault.Apply(plan))
+
+	_, err = os.Stat(path)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	moved, err := notes.ReadDocument(filepath.Join(vault.SyncDir, "personal", "2024-01-01T00_00_00Z.dj"))
+	require.NoError(t, err)
+	assert.Equal(t, "See [peer](../work/2024-03-01T00_00_00Z.dj)\n", moved.Body)
+	assert.Equal(t, []string{"work/2024-01-01T00_00_00Z.dj"}, moved.Header.GetList(notes.KeyMovedFrom))
+
+	peer, err := notes.ReadDocument(peerPath)
+	require.NoError(t, err)
+	assert.Equal(t, "See [moved](../personal/2024-01-01T00_00_00Z.dj#intro)\n", peer.Body)
+	assert.Equal(t, []string{"personal/2024-01-01T00_00_00Z.dj"}, peer.Header.GetList(notes.KeyLinks))
+}
+
+func TestPlanMoveInvalid(t *testing.T) {
+	vault := initTestVault(t, "work", "personal")
+	path := writeTestNote(t, vault, "work", "2024-01-01T00_00_00Z.dj", "")
+
+	_, err := vault.PlanMove(path, "work")
+	require.ErrorIs(t, err, notes.ErrSameSubDir)
+
+	_, err = vault.PlanMove(path, "missing")
+	require.ErrorIs(t, err, notes.ErrUnknownSubDir)
+
+	writeTestNote(t, vault, "personal", "2024-01-01T00_00_00Z.dj", "")
+	_, err = vault.PlanMove(path, "personal")
+	require.Error(t, err)
+}
+
+func TestCheckLinksMovedFrom(t *testing.T) {
+	vault := initTestVault(t, "work", "personal")
+	writeTestNote(t, vault, "personal", "2024-01-01T00_00_00Z.dj", ": moved-from=work/2024-01-01T00_00_00Z.dj\\\n")
+	writeTestNote(t, vault, "work", "2024-02-01T00_00_00Z.dj", "[stale](2024-01-01T00_00_00Z.dj)\n")
+
+	broken, err := vault.CheckLinks()
+	require.NoError(t, err)
+	require.Len(t, broken, 1)
+
+	fix, ok := broken[0].Fix()
+	assert.True(t, ok)
+	assert.Equal(t, "personal/2024-01-01T00_00_00Z.dj", fix)
+}
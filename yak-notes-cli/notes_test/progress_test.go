@@ -0,0 +1,42 @@
+package notes_test
+
+import (
+	"testing"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTasks(t *testing.T) {
+	body := "- [x] Done\n  - [ ] Nested\n* [X] Star\n\n```\n- [ ] In code\n```\n- Not a task\n"
+
+	assert.Equal(t, []notes.TaskItem{
+		{Done: true, Text: "Done"},
+		{Done: false, Text: "Nested"},
+		{Done: true, Text: "Star"},
+	}, notes.ParseTasks(body))
+}
+
+func TestTaskProgress(t *testing.T) {
+	vault := initTestVault(t, "work")
+	parent := writeTestNote(t, vault, "work", "2024-01-01T00_00_00Z.dj", ": state=complete\\\n\n"+
+		"- [x] Inline subtask\n"+
+		"- [x] [Child](2024-01-02T00_00_00Z.dj)\n"+
+		"- [ ] Open subtask\n"+
+		"See [other](2024-01-03T00_00_00Z.dj) and [reference](2024-01-04T00_00_00Z.dj)\n")
+	writeTestNote(t, vault, "work", "2024-01-02T00_00_00Z.dj", ": state=queue\\\n")
+	writeTestNote(t, vault, "work", "2024-01-03T00_00_00Z.dj", ": state=not-planned\\\n")
+	writeTestNote(t, vault, "work", "2024-01-04T00_00_00Z.dj", "Not a task\n")
+
+	progress, err := vault.TaskProgress()
+	require.NoError(t, err)
+	require.Len(t, progress, 1)
+
+	p := progress[parent]
+	assert.Equal(t, "2/4", p.String())
+	assert.Equal(t, 2, p.Open())
+	assert.True(t, p.Inconsistent())
+	assert.False(t, notes.Progress{State: notes.StateInProgress, Total: 1}.Inconsistent())
+	assert.Empty(t, notes.Progress{}.String())
+}
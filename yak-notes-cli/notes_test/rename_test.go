@@ -0,0 +1,116 @@
+package notes_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Write a note with a modification time that is older than any birth time set by the test
+func writeDatedNote(t *testing.T, vault *notes.Vault, name, content string, modTime time.Time) string {
+	t.Helper()
+
+	path := writeTestNote(t, vault, "work", name, content)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+
+	return path
+}
+
+func TestCreationTime(t *testing.T) {
+	vault := initTestVault(t, "work")
+	modTime := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name     string
+		content  string
+		source   notes.CreationSource
+		expected time.Time
+	}{
+		{"header.dj", ": creation_date=2020-05-06T07:08:09.123456\\\n", notes.CreationHeader, time.Date(2020, 5, 6, 7, 8, 9, 123456000, time.UTC)},
+		{"draft-2021-02-03T04_05_06Z.dj", "", notes.CreationName, time.Date(2021, 2, 3, 4, 5, 6, 0, time.UTC)},
+		{"future.dj", ": creation_date=2999-01-01\\\n", notes.CreationModTime, modTime},
+		{"plain.dj", "", notes.CreationModTime, modTime},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := writeDatedNote(t, vault, tc.name, tc.content, modTime)
+
+			created, source, err := notes.CreationTime(path)
+			require.NoError(t, err)
+			assert.Equal(t, tc.source, source)
+			assert.True(t, tc.expected.Equal(created), "expected %s, got %s", tc.expected, created)
+		})
+	}
+}
+
+func TestCreationTimeUntrusted(t *testing.T) {
+	vault := initTestVault(t, "work")
+	path := writeDatedNote(t, vault, "epoch.dj", "", time.Unix(0, 0))
+
+	_, _, err := notes.CreationTime(path)
+	require.ErrorIs(t, err, notes.ErrNoCreationTime)
+
+	_, _, err = vault.RenameNote(path)
+	require.ErrorIs(t, err, notes.ErrNoCreationTime)
+}
+
+func TestRenameNote(t *testing.T) {
+	vault := initTestVault(t, "work")
+	path := writeDatedNote(t, vault, "imported.dj", ": creation_date=2020-05-06T07:08:09Z\\\n", time.Now())
+
+	newPath, source, err := vault.RenameNote(path)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(filepath.Dir(path), "2020-05-06T07_08_09Z.dj"), newPath)
+	assert.Equal(t, notes.CreationHeader, source)
+}
+
+func TestPlanRenameAll(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "draft")
+	require.NoError(t, os.Mkdir(dir, 0o755))
+
+	header := ": creation_date=2020-05-06T07:08:09Z\\\n"
+	for name, content := range map[string]string{
+		"draft.dj":                header + "First\n",
+		"other.dj":                header + "Second\n",
+		"2020-05-06T07_08_09Z.dj": "Existing\n",
+		"2021-01-01T00_00_00Z.dj": "Conforming\n",
+		"attachment.png":          "",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	plan, err := notes.PlanRenameAll(dir)
+	require.NoError(t, err)
+	require.Len(t, plan, 2)
+
+	targets := []string{filepath.Base(plan[0].To), filepath.Base(plan[1].To)}
+	assert.Equal(t, []string{"2020-05-06T07_08_09Z-2.dj", "2020-05-06T07_08_09Z-3.dj"}, targets)
+	assert.Equal(t, dir, filepath.Dir(plan[0].To), "the directory must not be renamed")
+	assert.Contains(t, plan.String(), "(from the creation_date header)")
+
+	undoLog := notes.UndoLogPath(dir, time.Now())
+	require.NoError(t, plan.Apply(undoLog))
+
+	content, err := os.ReadFile(filepath.Join(dir, targets[0]))
+	require.NoError(t, err)
+	assert.Equal(t, header+"First\n", string(content))
+
+	_, err = os.Stat(filepath.Join(dir, "draft.dj"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	reverted, err := notes.UndoRenames(undoLog)
+	require.NoError(t, err)
+	assert.Len(t, reverted, 2)
+
+	for _, name := range []string{"draft.dj", "other.dj", "2020-05-06T07_08_09Z.dj"} {
+		_, err = os.Stat(filepath.Join(dir, name))
+		require.NoError(t, err, name)
+	}
+
+	_, err = os.Stat(undoLog)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
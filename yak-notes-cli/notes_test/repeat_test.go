@@ -0,0 +1,177 @@
+package notes_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepeatNext(t *testing.T) {
+	friday := time.Date(2024, 5, 10, 15, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		rule     string
+		after    time.Time
+		expected string
+	}{
+		{"daily", friday, "2024-05-11"},
+		{"weekly", friday, "2024-05-17"},
+		{"weekly:mon,thu", friday, "2024-05-13"},
+		{"weekly:Friday", friday, "2024-05-17"},
+		{"monthly", friday, "2024-06-10"},
+		{"monthly:15", friday, "2024-05-15"},
+		{"monthly:31", time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), "2024-02-29"},
+	} {
+		t.Run(tc.rule, func(t *testing.T) {
+			rule, err := notes.ParseRepeat(tc.rule)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, rule.Next(tc.after).Format(time.DateOnly))
+		})
+	}
+}
+
+// A monthly rule keeps the day of the first occurrence after it's clamped in a shorter month
+func TestRepeatNextPinned(t *testing.T) {
+	rule, err := notes.ParseRepeat("monthly")
+	require.NoError(t, err)
+
+	first := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+	rule = rule.Pinned(first)
+
+	february := rule.Next(first)
+	assert.Equal(t, "2025-02-28", february.Format(time.DateOnly))
+	assert.Equal(t, "2025-03-31", rule.Next(february).Format(time.DateOnly))
+}
+
+func TestParseRepeatInvalid(t *testing.T) {
+	for _, value := range []string{"", "yearly", "daily:2", "weekly:someday", "monthly:32"} {
+		_, err := notes.ParseRepeat(value)
+		require.ErrorIs(t, err, notes.ErrInvalidRepeat, value)
+	}
+}
+
+// The first Friday at least a week from today
+func upcomingFriday() time.Time {
+	y, m, d := time.Now().Date()
+	day := time.Date(y, m, d+7, 0, 0, 0, 0, time.UTC)
+
+	return day.AddDate(0, 0, (int(time.Friday)-int(day.Weekday())+7)%7)
+}
+
+func TestSetStateRepeat(t *testing.T) {
+	vault := initTestVault(t, "work")
+	friday := upcomingFriday()
+	content := ": state=in-progress\\\n: repeat=weekly:fri\\\n: soft-deadline=" + friday.Format(time.DateOnly) +
+		"\\\n: hard-deadline=" + friday.AddDate(0, 0, 1).Format(time.DateOnly) + "T12:00:00Z\\\n\n" +
+		"- [x] Water plants\n- [ ] Take out trash\n"
+	path := writeTestNote(t, vault, "work", "2024-01-02T03_04_05Z.dj", content)
+
+	change, err := vault.SetState(path, notes.StateComplete)
+	require.NoError(t, err)
+	assert.Equal(t, notes.StateInProgress, change.Previous)
+	require.NotEmpty(t, change.NextOccurrence)
+	assert.Equal(t, filepath.Dir(path), filepath.Dir(change.NextOccurrence))
+
+	_, err = notes.FromTimeName(strings.TrimSuffix(filepath.Base(change.NextOccurrence), notes.NoteExt))
+	require.NoError(t, err)
+
+	doc, err := notes.ReadDocument(change.NextOccurrence)
+	require.NoError(t, err)
+
+	expected := map[string]string{
+		notes.KeyRepeat:       "weekly:fri",
+		notes.KeyState:        string(notes.StateQueue),
+		notes.KeySoftDeadline: friday.AddDate(0, 0, 7).Format(time.DateOnly),
+		notes.KeyHardDeadline: friday.AddDate(0, 0, 8).Format(time.DateOnly) + "T12:00:00Z",
+		notes.KeyRepeatedFrom: "work/2024-01-02T03_04_05Z.dj",
+	}
+	for key, value := range expected {
+		actual, _ := doc.Header.Get(key)
+		assert.Equal(t, value, actual, key)
+	}
+
+	assert.Equal(t, "- [ ] Water plants\n- [ ] Take out trash\n", doc.Body)
+	assert.Contains(t, vault.NoteLinks(change.NextOccurrence, doc), "work/2024-01-02T03_04_05Z.dj")
+
+	// Completing the same note again doesn't create another occurrence
+	change, err = vault.SetState(path, notes.StateComplete)
+	require.NoError(t, err)
+	assert.Empty(t, change.NextOccurrence)
+}
+
+func TestSetStateRepeatLate(t *testing.T) {
+	vault := initTestVault(t, "work")
+	path := writeTestNote(t, vault, "work", "2024-01-02T03_04_05Z.dj",
+		": state=queue\\\n: repeat=weekly:fri\\\n: start-date=2024-05-09\\\n: soft-deadline=2024-05-10\\\n")
+
+	change, err := vault.SetState(path, notes.StateComplete)
+	require.NoError(t, err)
+
+	doc, err := notes.ReadDocument(change.NextOccurrence)
+	require.NoError(t, err)
+
+	schedule, err := doc.Header.Schedule()
+	require.NoError(t, err)
+
+	y, m, d := time.Now().Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+	assert.True(t, schedule.SoftDeadline.After(today), schedule.SoftDeadline)
+	assert.False(t, schedule.SoftDeadline.After(today.AddDate(0, 0, 7)), schedule.SoftDeadline)
+	assert.Equal(t, time.Friday, schedule.SoftDeadline.Weekday())
+	assert.Equal(t, schedule.SoftDeadline.AddDate(0, 0, -1), schedule.Start)
+}
+
+// Completing each occurrence of a monthly task from the 31st goes from Jan 31 to Feb 28 to Mar 31
+func TestSetStateRepeatMonthly(t *testing.T) {
+	vault := initTestVault(t, "work")
+
+	year := time.Now().Year() + 1
+	for year%4 == 0 {
+		year++
+	}
+
+	path := writeTestNote(t, vault, "work", "2024-01-02T03_04_05Z.dj",
+		fmt.Sprintf(": state=queue\\\n: repeat=monthly\\\n: soft-deadline=%d-01-31\\\n", year))
+
+	for _, expected := range []string{"02-28", "03-31"} {
+		change, err := vault.SetState(path, notes.StateComplete)
+		require.NoError(t, err)
+
+		doc, err := notes.ReadDocument(change.NextOccurrence)
+		require.NoError(t, err)
+
+		deadline, _ := doc.Header.Get(notes.KeySoftDeadline)
+		assert.Equal(t, fmt.Sprintf("%d-%s", year, expected), deadline)
+
+		rule, _ := doc.Header.Get(notes.KeyRepeat)
+		assert.Equal(t, "monthly:31", rule)
+
+		path = change.NextOccurrence
+	}
+}
+
+func TestSetStateRepeatWithoutSchedule(t *testing.T) {
+	vault := initTestVault(t, "work")
+	path := writeTestNote(t, vault, "work", "2024-01-02T03_04_05Z.dj", ": repeat=daily\\\n\nStretch\n")
+
+	before := time.Now()
+
+	change, err := vault.SetState(path, notes.StateComplete)
+	require.NoError(t, err)
+
+	doc, err := notes.ReadDocument(change.NextOccurrence)
+	require.NoError(t, err)
+
+	start, ok, err := doc.Header.GetTime(notes.KeyStartDate)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, before.AddDate(0, 0, 1).Format(time.DateOnly), start.Format(time.DateOnly))
+	assert.Equal(t, "Stretch\n", doc.Body)
+}
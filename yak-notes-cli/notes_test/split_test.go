@@ -0,0 +1,62 @@
+package notes_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanSplitHeadings(t *testing.T) {
+	vault := initTestVault(t, "work")
+	path := writeTestNote(t, vault, "work", "2024-01-01T00_00_00Z.dj", ": state=Atomic\\\n\n"+
+		"Intro\n\n## First\n\nOne\n\n```\n## Not a heading\n```\n\n## Second [draft]\n\n### Nested\n\nTwo\n")
+
+	plan, err := vault.PlanSplit(path, notes.DefaultSplitMarker)
+	require.NoError(t, err)
+	require.Len(t, plan, 3)
+	require.NoError(t, vault.Apply(plan))
+
+	first, err := notes.ReadDocument(plan[0].Path)
+	require.NoError(t, err)
+	assert.Equal(t, "## First\n\nOne\n\n```\n## Not a heading\n```\n", first.Body)
+	assert.Equal(t, []string{"work/2024-01-01T00_00_00Z.dj"}, first.Header.GetList(notes.KeySplitFrom))
+
+	second, err := notes.ReadDocument(plan[1].Path)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(second.Body, "## Second [draft]\n\n### Nested"))
+
+	index, err := notes.ReadDocument(path)
+	require.NoError(t, err)
+	assert.Equal(t, ": state=Atomic\\\n", index.Header.String())
+
+	links := notes.ParseLinks(index.Body)
+	require.Len(t, links, 2)
+	assert.Equal(t, filepath.Base(plan[0].Path), links[0].Dest)
+	assert.Equal(t, `Second \[draft\]`, links[1].Text)
+	assert.True(t, strings.HasPrefix(index.Body, "Intro\n\n- [First]("))
+}
+
+func TestPlanSplitMarkers(t *testing.T) {
+	vault := initTestVault(t, "work")
+	path := writeTestNote(t, vault, "work", "2024-01-01T00_00_00Z.dj", "# Title\n{% split %}\nOne\n{% split %}\nTwo\n")
+
+	plan, err := vault.PlanSplit(path, notes.DefaultSplitMarker)
+	require.NoError(t, err)
+	require.Len(t, plan, 3)
+	assert.Equal(t, ": split-from=work/2024-01-01T00_00_00Z.dj\\\n\nOne\n", plan[0].Content)
+	assert.Equal(t, ": split-from=work/2024-01-01T00_00_00Z.dj\\\n\nTwo\n", plan[1].Content)
+	assert.NotEqual(t, plan[0].Path, plan[1].Path)
+}
+
+func TestPlanSplitNothing(t *testing.T) {
+	vault := initTestVault(t, "work")
+	path := writeTestNote(t, vault, "work", "2024-01-01T00_00_00Z.dj", "Just text\n")
+
+	_, err := vault.PlanSplit(path, notes.DefaultSplitMarker)
+
+	require.ErrorIs(t, err, notes.ErrNothingToSplit)
+}
@@ -0,0 +1,28 @@
+package notes_test
+
+import (
+	"testing"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetState(t *testing.T) {
+	vault := initTestVault(t, "work")
+	path := writeTestNote(t, vault, "work", "2024-01-02T03_04_05Z.dj", ": state=backlog\\\n\nBody\n")
+
+	change, err := vault.SetState(path, notes.StateInProgress)
+	require.NoError(t, err)
+	assert.Equal(t, notes.StateBacklog, change.Previous)
+	assert.Empty(t, change.NextOccurrence)
+
+	doc, err := notes.ReadDocument(path)
+	require.NoError(t, err)
+
+	state, err := doc.Header.State()
+	require.NoError(t, err)
+	assert.Equal(t, notes.StateInProgress, state)
+	assert.Equal(t, []string{notes.KeyState, notes.KeyStateChanged}, doc.Header.Keys())
+	assert.Equal(t, "Body\n", doc.Body)
+}
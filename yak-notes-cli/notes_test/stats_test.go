@@ -0,0 +1,100 @@
+package notes_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	vault := initTestVault(t, "notes", "work", "empty")
+	writeTestNote(t, vault, "notes", "2024-01-01T00_00_00Z.dj", "# Hub\n\nOne two three\n")
+	writeTestNote(t, vault, "notes", "2024-01-03T00_00_00Z.dj",
+		": state=backlog\\\n\n[hub](2024-01-01T00_00_00Z.dj) and [self](2024-01-03T00_00_00Z.dj)\n")
+	writeTestNote(t, vault, "work", "2024-01-08T12_00_00Z.dj",
+		": links=notes/2024-01-01T00_00_00Z.dj\\\n\n[hub](../notes/2024-01-01T00_00_00Z.dj)\n")
+	alonePath := writeTestNote(t, vault, "work", "2024-01-09T00_00_00Z.dj", "Alone\n")
+
+	stats, err := vault.ListNotes("")
+	require.NoError(t, err)
+
+	modified := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	for _, stat := range stats {
+		require.NoError(t, os.Chtimes(stat.Path, modified, modified))
+	}
+
+	require.NoError(t, os.Chtimes(alonePath, modified, modified.AddDate(0, 0, 7)))
+
+	vaultStats, err := vault.Stats(1)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, vaultStats.Notes)
+	assert.Equal(t, 9, vaultStats.Words)
+	assert.Equal(t, []notes.Count{{Name: "notes", Count: 2}, {Name: "work", Count: 2}, {Name: "empty", Count: 0}}, vaultStats.SubDirs)
+	assert.Equal(t, []notes.Count{{Name: notes.NoState, Count: 3}, {Name: "backlog", Count: 1}}, vaultStats.States)
+
+	assert.Equal(t, []notes.WeekActivity{
+		{Week: "2024-01-01", Created: 2, Words: 7},
+		{Week: "2024-01-08", Created: 2, Modified: 3, Words: 2},
+		{Week: "2024-01-15", Modified: 1},
+	}, vaultStats.Weeks)
+
+	assert.Equal(t,
+		[]notes.LinkCount{{Note: "notes/2024-01-01T00_00_00Z.dj", Title: "Hub", Inbound: 2}},
+		vaultStats.MostLinked,
+	)
+	assert.Equal(t,
+		[]notes.LinkCount{{Note: "work/2024-01-09T00_00_00Z.dj", Title: "Alone"}},
+		vaultStats.MostOrphaned,
+	)
+}
+
+// Words are written in the week a note was created, then added words in the week it was modified, and removed words or
+// a note that is moved aren't counted again
+func TestStatsWordsWritten(t *testing.T) {
+	vault := initTestVault(t, "notes", "work")
+
+	edit := func(path, content string, modified time.Time) []notes.WeekActivity {
+		t.Helper()
+
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		require.NoError(t, os.Chtimes(path, modified, modified))
+
+		vaultStats, err := vault.Stats(0)
+		require.NoError(t, err)
+
+		return vaultStats.Weeks
+	}
+
+	path := filepath.Join(vault.SyncDir, "notes", "2024-01-01T00_00_00Z.dj")
+
+	assert.Equal(t, []notes.WeekActivity{
+		{Week: "2024-01-01", Created: 1, Words: 3},
+		{Week: "2024-01-08", Modified: 1},
+	}, edit(path, "One two three\n", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)))
+
+	assert.Equal(t, []notes.WeekActivity{
+		{Week: "2024-01-01", Created: 1, Words: 3},
+		{Week: "2024-01-22", Modified: 1, Words: 2},
+	}, edit(path, "One two three four five\n", time.Date(2024, 1, 24, 0, 0, 0, 0, time.UTC)))
+
+	assert.Equal(t, []notes.WeekActivity{
+		{Week: "2024-01-01", Created: 1, Words: 3},
+		{Week: "2024-01-22", Words: 2},
+		{Week: "2024-01-29", Modified: 1},
+	}, edit(path, "One\n", time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)))
+
+	moved := filepath.Join(vault.SyncDir, "work", filepath.Base(path))
+	require.NoError(t, os.Rename(path, moved))
+
+	assert.Equal(t, []notes.WeekActivity{
+		{Week: "2024-01-01", Created: 1, Words: 3},
+		{Week: "2024-01-22", Words: 2},
+		{Week: "2024-01-29", Modified: 1},
+	}, edit(moved, "One\n", time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,79 @@
+package notes_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestTemplate(t *testing.T, vault *notes.Vault, name, content string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(vault.SyncDir, notes.TemplatesDir), 0o755))
+	require.NoError(t, os.WriteFile(vault.TemplatePath(name), []byte(content), 0o600))
+}
+
+func TestRenderTemplate(t *testing.T) {
+	vault := initTestVault(t, "work", "personal")
+	writeTestTemplate(t, vault, "work", ": creation_date={{.Created}}\\\n: state=queue\\\n\n# {{.Title}} ({{.SubDir}}, {{.Date}})\n")
+	writeTestTemplate(t, vault, notes.DefaultTemplate, "{{.Time.Format \"Jan 2\"}}\n")
+
+	assert.Equal(t, "work", vault.SubDirTemplate("work"))
+	assert.Equal(t, notes.DefaultTemplate, vault.SubDirTemplate("personal"))
+
+	usesTitle, err := vault.TemplateUsesTitle("work")
+	require.NoError(t, err)
+	assert.True(t, usesTitle)
+
+	created := time.Date(2024, 5, 10, 15, 4, 5, 0, time.UTC)
+	content, err := vault.RenderTemplate("work", notes.NewTemplateData(created, "work", "Plan"))
+	require.NoError(t, err)
+	assert.Equal(t, ": creation_date=2024-05-10T15:04:05Z\\\n: state=queue\\\n\n# Plan (work, 2024-05-10)\n", content)
+
+	content, err = vault.RenderTemplate(notes.DefaultTemplate, notes.NewTemplateData(created, "personal", ""))
+	require.NoError(t, err)
+	assert.Equal(t, "May 10\n", content)
+
+	_, err = vault.RenderTemplate("missing", notes.TemplateData{})
+	require.ErrorIs(t, err, notes.ErrTemplateNotFound)
+}
+
+func TestSubDirTemplateMissing(t *testing.T) {
+	vault := initTestVault(t, "work")
+	assert.Empty(t, vault.SubDirTemplate("work"))
+}
+
+func TestCreateNoteWithContent(t *testing.T) {
+	vault := initTestVault(t, "work")
+	created := time.Date(2024, 5, 10, 15, 4, 5, 0, time.UTC)
+
+	path, err := vault.CreateNoteWithContent("work", created, "Body\n")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(vault.SyncDir, "work", "2024-05-10T15_04_05Z.dj"), path)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Body\n", string(content))
+
+	path, err = vault.CreateNoteWithContent("work", created, "Other\n")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(vault.SyncDir, "work", "2024-05-10T15_04_05Z-2.dj"), path)
+}
+
+func TestFillNote(t *testing.T) {
+	created := time.Date(2024, 5, 10, 15, 4, 5, 0, time.UTC)
+
+	assert.Equal(t,
+		": creation_date=2024-05-10T15:04:05Z\\\n: name=Groceries\\\n\n- Milk\n",
+		notes.FillNote("", created, "Groceries", "- Milk"),
+	)
+	assert.Equal(t,
+		": state=queue\\\n: creation_date=2024-05-10T15:04:05Z\\\n\n# Template\n\nBody\n",
+		notes.FillNote(": state=queue\\\n\n# Template\n", created, "", "Body\n"),
+	)
+}
@@ -0,0 +1,48 @@
+package notes_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderTerminal(t *testing.T) {
+	vault := initTestVault(t, "work")
+	writeTestNote(t, vault, "work", "2024-01-01T00_00_00Z.dj", "# Linked plan\n")
+	path := filepath.Join(vault.SyncDir, "work", "2024-01-02T00_00_00Z.dj")
+
+	doc := notes.ParseDocument(": state=queue\\\n: name=Demo\\\n\n" +
+		"# Title\n\n" +
+		"Some *strong* and _emph_ with `code` and a \\*literal\\*\n\n" +
+		"- item\n" +
+		"  1. nested\n" +
+		"- [ ] open\n" +
+		"- [x] done\n\n" +
+		"> quoted\n\n" +
+		"```go\n# not a heading\n```\n\n" +
+		"See [the plan](2024-01-01T00_00_00Z.dj), [](2024-01-01T00_00_00Z.dj), and [docs](https://djot.net)\n")
+
+	expected := "state: queue · name: Demo\n\n" +
+		"Title\n\n" +
+		"Some strong and emph with code and a *literal*\n\n" +
+		"• item\n" +
+		"  1. nested\n" +
+		"☐ open\n" +
+		"☑ done\n\n" +
+		"│ quoted\n\n" +
+		"  ┌ go\n  │ # not a heading\n  └\n\n" +
+		"See the plan (Linked plan), Linked plan, and docs <https://djot.net>\n"
+	assert.Equal(t, expected, vault.RenderTerminal(path, doc, false))
+}
+
+func TestRenderTerminalColor(t *testing.T) {
+	vault := initTestVault(t, "work")
+	doc := notes.ParseDocument("# Title\n\n*bold*\n")
+
+	rendered := vault.RenderTerminal(filepath.Join(vault.SyncDir, "work", "note.dj"), doc, true)
+	assert.Contains(t, rendered, "\x1b[")
+	assert.Contains(t, rendered, "Title")
+	assert.NotContains(t, rendered, "*bold*")
+}
@@ -0,0 +1,33 @@
+package notes_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeName(t *testing.T) {
+	now := time.Now()
+	name := notes.ToTimeName(now)
+
+	restored, err := notes.FromTimeName(name)
+
+	require.NoError(t, err)
+	assert.Equal(t, now.UTC().Format(time.RFC3339), restored.Format(time.RFC3339), name)
+}
+
+func TestTimeNameSuffix(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	name := notes.SuffixedTimeName(created, 2)
+	assert.Equal(t, "2024-01-02T03_04_05Z-2", name)
+
+	restored, err := notes.FromTimeName(name)
+	require.NoError(t, err)
+	assert.Equal(t, created, restored)
+
+	_, err = notes.FromTimeName("2024-01-02T03_04_05+01:00-2")
+	require.Error(t, err)
+}
@@ -0,0 +1,36 @@
+package notes_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTitle(t *testing.T) {
+	for _, tc := range []struct {
+		content  string
+		expected string
+	}{
+		{": name=Header name\\\n\nIntro\n\n## Heading\n", "Heading"},
+		{"```\n# Not a heading\n```\n\n# Real heading\n", "Real heading"},
+		{": name=Header name\\\n\nIntro\n", "Header name"},
+		{"\n\n  First line  \nSecond\n", "First line"},
+		{": state=queue\\\n", ""},
+		{strings.Repeat("a", 100), strings.Repeat("a", 79) + "…"},
+	} {
+		doc := notes.ParseDocument(tc.content)
+		assert.Equal(t, tc.expected, doc.Title(), tc.content)
+	}
+}
+
+func TestReadNoteTitle(t *testing.T) {
+	vault := initTestVault(t, "work")
+	writeTestNote(t, vault, "work", "2024-01-02T03_04_05Z.dj", "# Plan\n\nBody\n")
+
+	note, err := vault.ReadNote("work", "2024-01-02T03_04_05Z.dj")
+	require.NoError(t, err)
+	assert.Equal(t, "Plan", note.Title)
+}
@@ -0,0 +1,39 @@
+package notes_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+	"github.com/stretchr/testify/require"
+)
+
+// Create a vault in a temporary directory with the specified subDirs
+func initTestVault(t *testing.T, subDirs ...string) *notes.Vault {
+	t.Helper()
+
+	vault, err := notes.NewVault(t.TempDir())
+	require.NoError(t, err)
+
+	for _, subDir := range subDirs {
+		require.NoError(t, os.Mkdir(filepath.Join(vault.SyncDir, subDir), os.ModePerm))
+	}
+
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	vault.MigrationsDir = filepath.Join(filepath.Dir(cwd), "migrations")
+
+	return vault
+}
+
+// Write a note with the specified content and return the path
+func writeTestNote(t *testing.T, vault *notes.Vault, subDir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(vault.SyncDir, subDir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
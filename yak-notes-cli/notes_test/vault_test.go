@@ -0,0 +1,203 @@
+package notes_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListSubDirs(t *testing.T) {
+	vault := initTestVault(t, "work", ".trash", "personal")
+
+	subDirs, err := vault.ListSubDirs()
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"personal", "work"}, subDirs)
+}
+
+func TestListNotes(t *testing.T) {
+	vault := initTestVault(t, "work", "personal")
+	writeTestNote(t, vault, "work", "2024-01-02T03_04_05Z.dj", "")
+	writeTestNote(t, vault, "work", "ignored.txt", "")
+	writeTestNote(t, vault, "personal", "2024-02-02T03_04_05Z.dj", "")
+
+	all, err := vault.ListNotes("")
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	work, err := vault.ListNotes("work")
+	require.NoError(t, err)
+	require.Len(t, work, 1)
+	assert.Equal(t, "work", work[0].SubDir)
+	assert.Equal(t, "2024-01-02T03_04_05Z.dj", work[0].Name)
+}
+
+func TestCreateAndReadNote(t *testing.T) {
+	vault := initTestVault(t, "work")
+
+	path, err := vault.CreateNote("work")
+	require.NoError(t, err)
+
+	_, err = notes.FromTimeName(filepath.Base(path)[:len(filepath.Base(path))-len(notes.NoteExt)])
+	require.NoError(t, err)
+
+	note, err := vault.ReadNote("work", filepath.Base(path))
+	require.NoError(t, err)
+	assert.Empty(t, note.Content)
+	assert.Equal(t, "work", note.SubDir)
+}
+
+// Hidden names and other directories are rejected, so that trashed, archived, and template files can't be read
+func TestReadNoteHidden(t *testing.T) {
+	vault := initTestVault(t, "work", ".trash")
+	writeTestNote(t, vault, ".trash", "2024-01-02T03_04_05Z.dj", "Trashed")
+	writeTestNote(t, vault, "work", ".2024-01-02T03_04_05Z.dj.tmp", "Temporary")
+
+	for _, name := range [][2]string{
+		{".trash", "2024-01-02T03_04_05Z.dj"},
+		{".", "2024-01-02T03_04_05Z.dj"},
+		{"..", "2024-01-02T03_04_05Z.dj"},
+		{"work", ".2024-01-02T03_04_05Z.dj.tmp"},
+		{"work", "../.trash/2024-01-02T03_04_05Z.dj"},
+	} {
+		_, err := vault.ReadNote(name[0], name[1])
+		require.ErrorIs(t, err, notes.ErrInvalidName, name)
+	}
+
+	_, err := vault.ReadNote("missing", "2024-01-02T03_04_05Z.dj")
+	require.ErrorIs(t, err, notes.ErrUnknownSubDir)
+}
+
+func TestCreateNoteUnknownSubDir(t *testing.T) {
+	vault := initTestVault(t, "work")
+
+	_, err := vault.CreateNote("missing")
+
+	require.ErrorIs(t, err, notes.ErrUnknownSubDir)
+}
+
+func TestSearch(t *testing.T) {
+	vault := initTestVault(t, "work")
+	writeTestNote(t, vault, "work", "2024-01-02T03_04_05Z.dj", "First paragraph\n\nYak shaving")
+	writeTestNote(t, vault, "work", "2024-02-02T03_04_05Z.dj", "Unrelated")
+
+	results, err := vault.Search("yak", 10)
+
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "2024-01-02T03_04_05Z.dj", results[0].Filename)
+	assert.Equal(t, "First paragraph", results[0].Title)
+}
+
+func TestSearchTitleFirst(t *testing.T) {
+	vault := initTestVault(t, "work")
+	titled := writeTestNote(t, vault, "work", "2024-01-02T03_04_05Z.dj", "Yak shaving\n\nNotes")
+	writeTestNote(t, vault, "work", "2024-02-02T03_04_05Z.dj", "Unrelated\n\nMore yak shaving")
+	require.NoError(t, os.Chtimes(titled, time.Time{}, time.Now().Add(-time.Hour)))
+
+	results, err := vault.Search("yak", 10)
+
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "2024-01-02T03_04_05Z.dj", results[0].Filename)
+	assert.Equal(t, "2024-02-02T03_04_05Z.dj", results[1].Filename)
+}
+
+func TestSearchWithoutMigrations(t *testing.T) {
+	vault := initTestVault(t, "work")
+	vault.MigrationsDir = ""
+
+	_, err := vault.Search("yak", 10)
+
+	require.ErrorIs(t, err, notes.ErrNoMigrationsDir)
+}
+
+func TestFingerprint(t *testing.T) {
+	vault := initTestVault(t, "work")
+	path := writeTestNote(t, vault, "work", "2024-01-02T03_04_05Z.dj", "First")
+
+	first, err := vault.Fingerprint()
+	require.NoError(t, err)
+
+	unchanged, err := vault.Fingerprint()
+	require.NoError(t, err)
+	assert.Equal(t, first, unchanged)
+
+	require.NoError(t, os.Chtimes(path, time.Time{}, time.Now().Add(time.Minute)))
+
+	edited, err := vault.Fingerprint()
+	require.NoError(t, err)
+	assert.NotEqual(t, first, edited)
+
+	writeTestNote(t, vault, "work", "2024-02-02T03_04_05Z.dj", "Second")
+
+	added, err := vault.Fingerprint()
+	require.NoError(t, err)
+	assert.NotEqual(t, edited, added)
+}
+
+func TestResolve(t *testing.T) {
+	vault := initTestVault(t, "work")
+	path := writeTestNote(t, vault, "work", "2024-01-02T03_04_05Z.dj", "")
+
+	for _, ref := range []string{path, "work/2024-01-02T03_04_05Z.dj", "2024-01-02T03_04_05Z.dj", "2024-01-02T03_04_05Z"} {
+		stat, err := vault.Resolve(ref)
+		require.NoError(t, err, ref)
+		assert.Equal(t, path, stat.Path)
+		assert.Equal(t, "work", stat.SubDir)
+	}
+
+	_, err := vault.Resolve("missing")
+	require.ErrorIs(t, err, notes.ErrNoteNotFound)
+}
+
+func TestCreateNoteConcurrently(t *testing.T) {
+	vault := initTestVault(t, "work")
+	created := time.Date(2024, 5, 10, 15, 4, 5, 0, time.UTC)
+
+	const count = 50
+
+	paths := make([]string, count)
+	errs := make([]error, count)
+
+	var wg sync.WaitGroup
+	for i := range count {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			paths[i], errs[i] = vault.CreateNoteWithContent("work", created, fmt.Sprintf("Note %d\n", i))
+		}()
+	}
+
+	wg.Wait()
+
+	seen := map[string]bool{}
+
+	for i, path := range paths {
+		require.NoError(t, errs[i])
+		assert.False(t, seen[path], "duplicate path %s", path)
+		seen[path] = true
+
+		parsed, err := notes.FromTimeName(strings.TrimSuffix(filepath.Base(path), notes.NoteExt))
+		require.NoError(t, err)
+		assert.Equal(t, created, parsed)
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("Note %d\n", i), string(content), "notes must not overwrite each other")
+	}
+
+	stats, err := vault.ListNotes("work")
+	require.NoError(t, err)
+	assert.Len(t, stats, count)
+}
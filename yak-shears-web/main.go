@@ -1,16 +1,148 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"html/template"
+	"log"
+	"net/http"
 	"os"
+	"sort"
+	"sync"
+	"time"
 
-	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd"
+	"github.com/KyleKing/yak-shears/yak-notes-cli/cmd/config"
+	"github.com/KyleKing/yak-shears/yak-notes-cli/notes"
 )
 
-func main() {
-	cli := cmd.InitCli()
+// PLANNED: replace with templ components
+var pageTmpl = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Yak Shears</title></head>
+<body>
+<form action="/search"><input name="q" value="{{.Query}}"><button>Search</button></form>
+{{with .Note}}<h1>{{or .Title .Filename}}</h1><p>{{.SubDir}}/{{.Filename}}</p><pre>{{.Content}}</pre>{{end}}
+<ul>
+{{range .Stats}}<li><a href="/note/{{.SubDir}}/{{.Name}}">{{.SubDir}}/{{.Name}}</a></li>
+{{end}}{{range .Results}}<li><a href="/note/{{.SubDir}}/{{.Filename}}">{{.SubDir}}/{{.Filename}}</a> {{.Title}}</li>
+{{end}}</ul>
+</body>
+</html>
+`))
+
+type page struct {
+	Query   string
+	Note    *notes.Note
+	Stats   []notes.FileStat
+	Results []notes.Note
+}
+
+type server struct {
+	vault *notes.Vault
+	// mu serializes searches, which share the index and rebuild it when the notes changed since it was indexed
+	mu      sync.Mutex
+	indexed string
+}
+
+func (s *server) render(w http.ResponseWriter, p page) {
+	if err := pageTmpl.Execute(w, p); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *server) handleList(w http.ResponseWriter, _ *http.Request) {
+	stats, err := s.vault.ListNotes("")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].ModTime.After(stats[j].ModTime)
+	})
+
+	s.render(w, page{Stats: stats})
+}
+
+func (s *server) handleNote(w http.ResponseWriter, r *http.Request) {
+	note, err := s.vault.ReadNote(r.PathValue("subDir"), r.PathValue("filename"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	s.render(w, page{Note: &note})
+}
+
+// Search the index, which is only rebuilt when a note was added, removed, or edited
+func (s *server) search(query string, limit int) ([]notes.Note, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fingerprint, err := s.vault.Fingerprint()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := s.vault.OpenIndex()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
 
-	if err := cli.Run(); err != nil {
+	if fingerprint != s.indexed {
+		if err := s.vault.Reindex(db); err != nil {
+			return nil, err
+		}
+
+		s.indexed = fingerprint
+	}
+
+	return notes.SearchIndex(db, query, limit)
+}
+
+func (s *server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+
+	results, err := s.search(query, 20)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.render(w, page{Query: query, Results: results})
+}
+
+func run() error {
+	addr := flag.String("addr", "localhost:8080", "Address to listen on")
+	syncDir := flag.String("sync-dir", config.GetSyncDir(), "Sync Directory")
+	flag.Parse()
+
+	vault, err := notes.NewVault(*syncDir)
+	if err != nil {
+		return fmt.Errorf("failed to open vault: %w", err)
+	}
+
+	vault.MigrationsDir = config.GetMigrationsDir()
+	s := &server{vault: vault}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleList)
+	mux.HandleFunc("GET /note/{subDir}/{filename}", s.handleNote)
+	mux.HandleFunc("GET /search", s.handleSearch)
+
+	log.Printf("Serving %s on http://%s", vault.SyncDir, *addr)
+
+	srv := &http.Server{Addr: *addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	if err := srv.ListenAndServe(); err != nil {
+		return fmt.Errorf("server stopped: %w", err)
+	}
+
+	return nil
+}
+
+func main() {
+	if err := run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error encountered: %v\n", err)
 		os.Exit(1)
 	}